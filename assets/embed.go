@@ -0,0 +1,11 @@
+//go:build !noassets
+
+// Package assets holds the textures and music of the original intro.
+package assets
+
+import "embed"
+
+// FS has the textures under img/ and the music under audio/.
+//
+//go:embed img/*.png audio/*.wav
+var FS embed.FS
@@ -0,0 +1,10 @@
+//go:build noassets
+
+// Package assets holds the textures and music of the original intro.
+package assets
+
+import "embed"
+
+// Built with -tags noassets: nothing is embedded and the assets have to come
+// from -assets or -assets-url.
+var FS embed.FS
@@ -0,0 +1,92 @@
+package hbc
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// AdaptiveConfig thins out the bubbles while the frame rate is below its
+// target and brings them back once it recovers.
+type AdaptiveConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// TargetFPS defaults to 60.
+	TargetFPS float64 `json:"target_fps,omitempty"`
+	// Min and Max bound the share of the bubbles drawn, from 0 to 1. They
+	// default to 0.2 and 1.
+	Min float64 `json:"min,omitempty"`
+	Max float64 `json:"max,omitempty"`
+}
+
+const (
+	// ActualFPS is averaged over about a second, so the share is only
+	// adjusted that often.
+	adaptiveInterval = 60
+	// The share drops quickly when frames are missed and grows back
+	// slowly, so it does not oscillate around the limit.
+	adaptiveDown = 0.8
+	adaptiveUp   = 1.05
+	// adaptiveSlack is how far below the target the frame rate may dip
+	// before bubbles are dropped.
+	adaptiveSlack = 0.95
+)
+
+// bounds returns the configured limits with their defaults.
+func (c AdaptiveConfig) bounds() (target, lo, hi float64) {
+	target, lo, hi = c.TargetFPS, c.Min, c.Max
+	if target <= 0 {
+		target = 60
+	}
+	if hi <= 0 || hi > 1 {
+		hi = 1
+	}
+	if lo <= 0 {
+		lo = 0.2
+	}
+	return target, min(lo, hi), hi
+}
+
+func (g *Intro) setupAdaptive() {
+	g.bubbleShare = 1
+	if g.cfg.Adaptive.Enabled {
+		_, _, g.bubbleShare = g.cfg.Adaptive.bounds()
+	}
+}
+
+func (g *Intro) updateAdaptive() {
+	c := g.cfg.Adaptive
+	if !c.Enabled || g.offline || g.ticks%adaptiveInterval != 0 {
+		return
+	}
+	target, lo, hi := c.bounds()
+	fps := ebiten.ActualFPS()
+	switch {
+	case fps < target*adaptiveSlack:
+		g.bubbleShare = max(g.bubbleShare*adaptiveDown, lo)
+	case fps >= target-0.5:
+		g.bubbleShare = min(g.bubbleShare*adaptiveUp, hi)
+	}
+}
+
+// bubbleShown reports whether the i-th scripted bubble is within the share
+// being drawn. Bubbles are picked by a fixed scattered order, so the ones
+// dropped are spread over the whole screen and the same ones come back.
+func (g *Intro) bubbleShown(i int) bool {
+	share := g.bubbleShare
+	if g.mini.active {
+		share *= miniBubbleShare
+	}
+	if share >= 1 {
+		return true
+	}
+	_, frac := math.Modf(float64(i) * math.Phi)
+	return frac < share
+}
+
+func (g *Intro) adaptiveStatus() string {
+	if !g.cfg.Adaptive.Enabled {
+		return ""
+	}
+	return fmt.Sprintf("Bubbles: %.0f%%", g.bubbleShare*100)
+}
@@ -0,0 +1,78 @@
+package hbc
+
+import (
+	"log"
+	"math"
+	"math/rand"
+)
+
+const (
+	// The ambience plays while the view is still under water, fading out
+	// over the last ambienceFadeFrames as the waves settle.
+	ambienceFrames     = 240
+	ambienceFadeFrames = 60
+	ambienceVolume     = 0.25
+)
+
+// ambienceSound is a low underwater rumble: brown noise, smoothed once more
+// and swelling slowly. It is generated the first time it is needed.
+func ambienceSound() sound {
+	r := rand.New(rand.NewSource(1))
+	s := make(sound, (ambienceFrames+30)*sampleRate/60)
+	brown, smooth := 0.0, 0.0
+	for i := range s {
+		brown = (brown + (r.Float64()*2-1)*0.02) * 0.998
+		smooth += (brown - smooth) * 0.05
+		swell := 0.75 + 0.25*math.Sin(2*math.Pi*0.4*float64(i)/sampleRate)
+		s[i] = min(max(smooth*8*swell, -1), 1)
+	}
+	return s
+}
+
+func ambienceLevel(frame int) float64 {
+	return min(max(float64(ambienceFrames-frame)/ambienceFadeFrames, 0), 1)
+}
+
+// updateAmbience keeps the ambience channel in step with the animation. It
+// is started at the matching position whenever the frame is within the
+// opening, which also covers seeking back to it.
+func (g *Intro) updateAmbience() {
+	frame := g.count + g.audioLead()
+	if !g.cfg.Ambience || g.audioContext == nil || g.silent || g.offline ||
+		g.loopCount > 0 || frame < 0 || frame >= ambienceFrames {
+		g.stopAmbience()
+		return
+	}
+
+	if g.mixer.ambience == nil {
+		if g.mixer.ambienceSamples == nil {
+			g.mixer.ambienceSamples = ambienceSound().render(0)
+		}
+		g.mixer.ambience = g.audioContext.NewPlayerFromBytes(g.mixer.ambienceSamples)
+		g.setBufferSize(g.mixer.ambience)
+		if err := g.mixer.ambience.SetPosition(frameTime(frame)); err != nil {
+			log.Printf("Warning: Could not seek ambience: %v\n", err)
+		}
+	}
+	g.setAmbienceVolume(g.baseVolume() * g.shutdownLevel())
+	if !g.mixer.ambience.IsPlaying() {
+		g.mixer.ambience.Play()
+	}
+}
+
+func (g *Intro) setAmbienceVolume(volume float64) {
+	if g.mixer.ambience != nil {
+		g.mixer.ambience.SetVolume(volume * ambienceVolume * ambienceLevel(g.count+g.audioLead()))
+	}
+}
+
+func (g *Intro) stopAmbience() {
+	if g.mixer.ambience == nil {
+		return
+	}
+	g.mixer.ambience.Pause()
+	if err := g.mixer.ambience.Close(); err != nil {
+		log.Printf("Warning: Could not close audio player: %v\n", err)
+	}
+	g.mixer.ambience = nil
+}
@@ -0,0 +1,148 @@
+package hbc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"io"
+	"os"
+)
+
+// apngSink writes an animated PNG, which keeps full 24-bit color and 8-bit
+// alpha, unlike GIF. Frames are compressed as they come in; the file is
+// assembled on close, once the frame count for the acTL chunk is known.
+type apngSink struct {
+	path   string
+	step   int
+	bounds image.Rectangle
+	frames [][]byte
+}
+
+func (s *apngSink) add(frame int, img *image.RGBA) error {
+	if len(s.frames) == 0 {
+		s.bounds = img.Bounds()
+	}
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	w := s.bounds.Dx() * 4
+	row := make([]byte, 1+w)
+	pix := make([]byte, w)
+	for y := 0; y < s.bounds.Dy(); y++ {
+		// PNG stores straight alpha; ebiten's pixels are premultiplied.
+		copy(pix, img.Pix[y*img.Stride:y*img.Stride+w])
+		for x := 0; x < w; x += 4 {
+			if a := uint32(pix[x+3]); a > 0 && a < 255 {
+				for c := range 3 {
+					pix[x+c] = uint8(uint32(pix[x+c]) * 255 / a)
+				}
+			}
+		}
+		// The Sub filter stores each byte as the difference to the pixel on
+		// its left, which compresses the smooth gradients well.
+		row[0] = 1
+		for x := range pix {
+			if x < 4 {
+				row[1+x] = pix[x]
+			} else {
+				row[1+x] = pix[x] - pix[x-4]
+			}
+		}
+		if _, err := zw.Write(row); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	s.frames = append(s.frames, buf.Bytes())
+	return nil
+}
+
+func (s *apngSink) close() error {
+	f, err := os.Create(s.path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := s.write(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *apngSink) write(w io.Writer) error {
+	if _, err := io.WriteString(w, "\x89PNG\r\n\x1a\n"); err != nil {
+		return err
+	}
+
+	width, height := uint32(s.bounds.Dx()), uint32(s.bounds.Dy())
+	ihdr := binary.BigEndian.AppendUint32(nil, width)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, height)
+	ihdr = append(ihdr, 8, 6, 0, 0, 0) // 8-bit RGBA, no interlacing
+	if err := writeChunk(w, "IHDR", ihdr); err != nil {
+		return err
+	}
+
+	actl := binary.BigEndian.AppendUint32(nil, uint32(len(s.frames)))
+	actl = binary.BigEndian.AppendUint32(actl, 0) // loop forever
+	if err := writeChunk(w, "acTL", actl); err != nil {
+		return err
+	}
+
+	// fcTL and fdAT chunks share one sequence counter.
+	seq := uint32(0)
+	for i, data := range s.frames {
+		fctl := binary.BigEndian.AppendUint32(nil, seq)
+		fctl = binary.BigEndian.AppendUint32(fctl, width)
+		fctl = binary.BigEndian.AppendUint32(fctl, height)
+		fctl = binary.BigEndian.AppendUint32(fctl, 0)
+		fctl = binary.BigEndian.AppendUint32(fctl, 0)
+		fctl = binary.BigEndian.AppendUint16(fctl, uint16(s.step)) // delay of step/60 s
+		fctl = binary.BigEndian.AppendUint16(fctl, 60)
+		fctl = append(fctl, 0, 0) // no disposal, replace the previous frame
+		if err := writeChunk(w, "fcTL", fctl); err != nil {
+			return err
+		}
+		seq++
+
+		// The first frame doubles as the still image shown by viewers
+		// without APNG support.
+		var err error
+		if i == 0 {
+			err = writeChunk(w, "IDAT", data)
+		} else {
+			err = writeChunk(w, "fdAT", append(binary.BigEndian.AppendUint32(nil, seq), data...))
+			seq++
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return writeChunk(w, "IEND", nil)
+}
+
+func writeChunk(w io.Writer, typ string, data []byte) error {
+	header := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	header = append(header, typ...)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	_, err := w.Write(binary.BigEndian.AppendUint32(nil, crc.Sum32()))
+	return err
+}
@@ -0,0 +1,445 @@
+package hbc
+
+import (
+	"fmt"
+	"hash/fnv"
+	"image/color"
+	"log"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// After the intro the real Homebrew Channel shows its menu: a grid of app
+// banners over the same water, a page at a time. The app grid scene
+// recreates it, with the intro carrying on underneath minus the title.
+
+const (
+	appColumns     = 4
+	appRows        = 3
+	appsPerPage    = appColumns * appRows
+	appIconWidth   = 128
+	appIconHeight  = 48
+	appTileWidth   = 160
+	appTileHeight  = 80
+	appTileGap     = 22
+	appNameSize    = 13
+	appFooterSize  = 16
+	appFadeFrames  = 30
+	appScrollEase  = 0.18
+	appArrowMargin = 20
+)
+
+var (
+	appTileColor   = color.RGBA{255, 255, 255, 210}
+	appTileBorder  = color.RGBA{160, 200, 220, 255}
+	appHoverBorder = color.RGBA{52, 190, 237, 255}
+	appTextColor   = color.RGBA{60, 70, 80, 255}
+)
+
+type AppsConfig struct {
+	// AfterLoops opens the app grid once this many loops have played. Zero
+	// only opens it when the apps key is pressed.
+	AfterLoops int `json:"after_loops,omitempty"`
+	// Apps are shown in the grid in order.
+	Apps []AppConfig `json:"apps,omitempty"`
+	// Dir is an apps folder laid out like on an SD card, one folder per
+	// app with its icon.png and meta.xml. Its apps follow those listed
+	// above. Without either the grid shows a few well known homebrew apps.
+	Dir string `json:"dir,omitempty"`
+}
+
+type AppConfig struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+var defaultApps = []AppConfig{
+	{Name: "Homebrew Browser", Description: "Download and update homebrew over the internet"},
+	{Name: "WiiXplorer", Description: "File manager for SD cards, USB drives and the network"},
+	{Name: "Savegame Manager", Description: "Back up and restore save games"},
+	{Name: "ScummVM", Description: "Play classic point and click adventures"},
+	{Name: "SNES9x GX", Description: "Super Nintendo emulator"},
+	{Name: "WiiMC", Description: "Media center for music, video and pictures"},
+	{Name: "DOSBox", Description: "DOS emulator"},
+	{Name: "go-hbc-intro", Description: "The banner you just watched"},
+}
+
+// appTile is an app as shown in the grid.
+type appTile struct {
+	name string
+	// about is the version and author, from meta.xml.
+	about       string
+	description string
+	icon        *ebiten.Image
+}
+
+func (g *Intro) setupApps() {
+	n := g.cfg.Apps.AfterLoops
+	if n <= 0 {
+		return
+	}
+	g.Subscribe(EventLoopWrapped, func(e Event) {
+		if e.Loop == n {
+			g.appsRequested = true
+		}
+	})
+}
+
+func (g *Intro) appTiles() []appTile {
+	var tiles []appTile
+	for _, a := range g.cfg.Apps.Apps {
+		tiles = append(tiles, appTile{name: a.Name, description: a.Description})
+	}
+	if dir := g.cfg.Apps.Dir; dir != "" {
+		found, err := scanAppsDir(dir)
+		if err != nil {
+			log.Printf("Warning: Could not read apps directory: %v\n", err)
+		}
+		tiles = append(tiles, found...)
+	}
+	if len(tiles) == 0 {
+		for _, a := range defaultApps {
+			tiles = append(tiles, appTile{name: a.Name, description: a.Description})
+		}
+	}
+	return tiles
+}
+
+// startApps switches from the intro to the app grid once it has been asked
+// for.
+func (a *App) startApps() {
+	if a.scene != a.home || !a.game.appsRequested {
+		return
+	}
+	a.game.appsRequested = false
+	a.SetScene(newAppGridScene(a, a.game))
+}
+
+// appGridScene pages through the apps with the arrow keys, the mouse wheel
+// or the arrows at the sides. Pointing at a tile highlights it, clicking
+// shows its description, and the apps key goes back to the intro.
+type appGridScene struct {
+	app   *App
+	game  *Intro
+	tiles []appTile
+	page  int
+	// scroll eases towards page, in pages.
+	scroll   float64
+	hovered  int
+	selected int
+	// fade runs up to appFadeFrames as the grid appears, and back down to
+	// zero before it closes.
+	fade    int
+	closing bool
+}
+
+func newAppGridScene(app *App, g *Intro) *appGridScene {
+	g.appGridOpen = true
+	return &appGridScene{app: app, game: g, tiles: g.appTiles(), hovered: -1, selected: -1}
+}
+
+func (s *appGridScene) pages() int {
+	return max((len(s.tiles)+appsPerPage-1)/appsPerPage, 1)
+}
+
+func (s *appGridScene) Update() error {
+	g := s.game
+	if err := g.Update(); err != nil {
+		return err
+	}
+	if g.appsRequested {
+		g.appsRequested = false
+		s.closing = true
+	}
+	if s.closing {
+		if s.fade--; s.fade <= 0 {
+			g.appGridOpen = false
+			s.app.SetScene(s.app.home)
+		}
+		return nil
+	}
+	s.fade = min(s.fade+1, appFadeFrames)
+	if g.kiosk {
+		// Visitors cannot page through the grid or pick a tile.
+		return nil
+	}
+
+	_, wheel := ebiten.Wheel()
+	x, y := g.cursorPosition()
+	clicked := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	switch {
+	case g.settingsOpen:
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight), wheel < 0,
+		clicked && x > g.width-appArrowMargin*2:
+		s.turnPage(1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft), wheel > 0,
+		clicked && x < appArrowMargin*2:
+		s.turnPage(-1)
+	}
+	s.scroll += (float64(s.page) - s.scroll) * appScrollEase
+	if math.Abs(float64(s.page)-s.scroll) < 0.001 {
+		s.scroll = float64(s.page)
+	}
+
+	hovered := s.tileAt(x, y)
+	if hovered >= 0 && hovered != s.hovered {
+		g.playSound(hoverSound, (x-g.width/2)/(g.width/2))
+	}
+	s.hovered = hovered
+	if clicked && hovered >= 0 {
+		s.selected = hovered
+		g.playSound(clickSound, (x-g.width/2)/(g.width/2))
+	}
+	return nil
+}
+
+func (s *appGridScene) turnPage(dir int) {
+	page := min(max(s.page+dir, 0), s.pages()-1)
+	if page != s.page {
+		s.page = page
+		s.game.playSound(clickSound, float64(dir)*0.5)
+	}
+}
+
+// tileRect is where the i-th tile lies at the current scroll, in layout
+// coordinates.
+func (s *appGridScene) tileRect(i int) (x, y float64) {
+	g := s.game
+	page, slot := i/appsPerPage, i%appsPerPage
+	gridWidth := float64(appColumns*appTileWidth + (appColumns-1)*appTileGap)
+	gridHeight := float64(appRows*appTileHeight + (appRows-1)*appTileGap)
+	x = (g.width-gridWidth)/2 + float64(slot%appColumns)*(appTileWidth+appTileGap)
+	y = (g.height-gridHeight)/2 - 20 + float64(slot/appColumns)*(appTileHeight+appTileGap)
+	return x + (float64(page)-s.scroll)*g.width, y
+}
+
+func (s *appGridScene) tileAt(x, y float64) int {
+	if s.scroll != float64(s.page) {
+		return -1
+	}
+	for i := s.page * appsPerPage; i < min((s.page+1)*appsPerPage, len(s.tiles)); i++ {
+		tx, ty := s.tileRect(i)
+		if x >= tx && x < tx+appTileWidth && y >= ty && y < ty+appTileHeight {
+			return i
+		}
+	}
+	return -1
+}
+
+// Draw shows the water without the title under the grid, then the
+// overlays.
+func (s *appGridScene) Draw(screen *ebiten.Image) {
+	g := s.game
+	for _, l := range g.layers {
+		if !l.layer.overlay() && l.layer != LayerTitle {
+			g.blend = g.layerBlends[l.layer]
+			l.draw(screen)
+		}
+	}
+	g.blend = ebiten.Blend{}
+
+	ui := g.uiLayer(screen)
+	s.drawGrid(ui)
+	g.flushUILayer(screen, ui)
+	g.takeScreenshot(screen)
+
+	ui = g.uiLayer(screen)
+	for _, l := range g.layers {
+		if l.layer.overlay() {
+			l.draw(ui)
+		}
+	}
+	g.flushUILayer(screen, ui)
+}
+
+func (s *appGridScene) drawGrid(ui *ebiten.Image) {
+	g := s.game
+	alpha := float32(s.fade) / appFadeFrames
+	alpha = alpha * alpha * (3 - 2*alpha)
+	name := uiFace(appNameSize)
+
+	for i, tile := range s.tiles {
+		x, y := s.tileRect(i)
+		if x+appTileWidth < 0 || x > g.width {
+			continue
+		}
+		border := appTileBorder
+		if i == s.hovered || i == s.selected {
+			border = appHoverBorder
+		}
+		drawRoundedRect(ui, float32(x), float32(y), appTileWidth, appTileHeight, 10, scaleAlpha(appTileColor, alpha), scaleAlpha(border, alpha))
+
+		ix, iy := x+(appTileWidth-appIconWidth)/2, y+8
+		if tile.icon == nil {
+			s.tiles[i].icon = appIconPlaceholder(tile.name)
+			tile.icon = s.tiles[i].icon
+		}
+		op := &ebiten.DrawImageOptions{}
+		b := tile.icon.Bounds()
+		op.GeoM.Scale(appIconWidth/float64(b.Dx()), appIconHeight/float64(b.Dy()))
+		op.GeoM.Translate(ix, iy)
+		op.ColorScale.ScaleAlpha(alpha)
+		op.Filter = ebiten.FilterLinear
+		ui.DrawImage(tile.icon, op)
+
+		top := &text.DrawOptions{}
+		top.GeoM.Translate(x+appTileWidth/2, iy+appIconHeight+4)
+		top.PrimaryAlign = text.AlignCenter
+		top.ColorScale.ScaleWithColor(appTextColor)
+		top.ColorScale.ScaleAlpha(alpha)
+		text.Draw(ui, tile.name, name, top)
+	}
+
+	arrow := scaleAlpha(color.RGBA{255, 255, 255, 230}, alpha)
+	mid := float32(g.height/2 - 20)
+	if s.page > 0 {
+		drawArrow(ui, appArrowMargin, mid, -1, arrow)
+	}
+	if s.page < s.pages()-1 {
+		drawArrow(ui, float32(g.width)-appArrowMargin, mid, 1, arrow)
+	}
+
+	footer := ""
+	if s.selected >= 0 {
+		t := s.tiles[s.selected]
+		footer = t.name
+		if t.about != "" {
+			footer += " " + t.about
+		}
+		if t.description != "" {
+			footer += " - " + t.description
+		}
+	} else if s.pages() > 1 {
+		footer = pageLabel(s.page, s.pages())
+	}
+	if footer != "" {
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(g.width/2, g.height-48)
+		op.PrimaryAlign = text.AlignCenter
+		op.ColorScale.ScaleWithColor(appTextColor)
+		op.ColorScale.ScaleAlpha(alpha)
+		text.Draw(ui, footer, uiFace(appFooterSize), op)
+	}
+}
+
+func (s *appGridScene) drawsThroughView() {}
+
+func pageLabel(page, pages int) string {
+	return fmt.Sprintf("Page %d of %d", page+1, pages)
+}
+
+// appIconPlaceholder is a banner for an app without an icon: a gradient in a
+// color picked from its name, with its initials.
+func appIconPlaceholder(name string) *ebiten.Image {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	hue := float64(h.Sum32()%360) / 360
+
+	img := ebiten.NewImage(appIconWidth, appIconHeight)
+	for y := range appIconHeight {
+		light := 0.62 - 0.22*float64(y)/appIconHeight
+		vector.DrawFilledRect(img, 0, float32(y), appIconWidth, 1, hsl(hue, 0.55, light), false)
+	}
+
+	var initials []rune
+	for _, word := range strings.Fields(name) {
+		if len(initials) < 2 {
+			initials = append(initials, unicode.ToUpper([]rune(word)[0]))
+		}
+	}
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(appIconWidth/2, appIconHeight/2)
+	op.PrimaryAlign = text.AlignCenter
+	op.SecondaryAlign = text.AlignCenter
+	text.Draw(img, string(initials), uiFace(26), op)
+	return img
+}
+
+// hsl converts a hue, saturation and lightness, each from 0 to 1, to RGB.
+func hsl(h, s, l float64) color.RGBA {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h*6, 2)-1))
+	m := l - c/2
+	var r, g, b float64
+	switch int(h * 6) {
+	case 0:
+		r, g, b = c, x, 0
+	case 1:
+		r, g, b = x, c, 0
+	case 2:
+		r, g, b = 0, c, x
+	case 3:
+		r, g, b = 0, x, c
+	case 4:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return color.RGBA{uint8((r + m) * 255), uint8((g + m) * 255), uint8((b + m) * 255), 255}
+}
+
+func scaleAlpha(c color.RGBA, a float32) color.RGBA {
+	return color.RGBA{uint8(float32(c.R) * a), uint8(float32(c.G) * a), uint8(float32(c.B) * a), uint8(float32(c.A) * a)}
+}
+
+func drawRoundedRect(dst *ebiten.Image, x, y, w, h, r float32, fill, border color.RGBA) {
+	var p vector.Path
+	p.MoveTo(x+r, y)
+	p.LineTo(x+w-r, y)
+	p.ArcTo(x+w, y, x+w, y+r, r)
+	p.LineTo(x+w, y+h-r)
+	p.ArcTo(x+w, y+h, x+w-r, y+h, r)
+	p.LineTo(x+r, y+h)
+	p.ArcTo(x, y+h, x, y+h-r, r)
+	p.LineTo(x, y+r)
+	p.ArcTo(x, y, x+r, y, r)
+	p.Close()
+	fillVertices(dst, &p, nil, fill)
+	fillVertices(dst, &p, &vector.StrokeOptions{Width: 2}, border)
+}
+
+func drawArrow(dst *ebiten.Image, x, y, dir float32, c color.RGBA) {
+	var p vector.Path
+	p.MoveTo(x+dir*8, y)
+	p.LineTo(x-dir*6, y-14)
+	p.LineTo(x-dir*6, y+14)
+	p.Close()
+	fillVertices(dst, &p, nil, c)
+}
+
+// fillVertices fills p, or strokes it when stroke is set, on dst in its own
+// pixels, with c premultiplied.
+func fillVertices(dst *ebiten.Image, p *vector.Path, stroke *vector.StrokeOptions, c color.RGBA) {
+	fillVerticesGeoM(dst, p, stroke, ebiten.GeoM{}, c)
+}
+
+// fillVerticesGeoM is fillVertices with p placed by geoM.
+func fillVerticesGeoM(dst *ebiten.Image, p *vector.Path, stroke *vector.StrokeOptions, geoM ebiten.GeoM, c color.RGBA) {
+	var vs []ebiten.Vertex
+	var is []uint16
+	if stroke != nil {
+		vs, is = p.AppendVerticesAndIndicesForStroke(nil, nil, stroke)
+	} else {
+		vs, is = p.AppendVerticesAndIndicesForFilling(nil, nil)
+	}
+	for i := range vs {
+		x, y := geoM.Apply(float64(vs[i].DstX), float64(vs[i].DstY))
+		vs[i].DstX, vs[i].DstY = float32(x), float32(y)
+		vs[i].SrcX, vs[i].SrcY = 1, 1
+		vs[i].ColorR = float32(c.R) / 0xff
+		vs[i].ColorG = float32(c.G) / 0xff
+		vs[i].ColorB = float32(c.B) / 0xff
+		vs[i].ColorA = float32(c.A) / 0xff
+	}
+	dst.DrawTriangles(vs, is, solidSource(), &ebiten.DrawTrianglesOptions{
+		ColorScaleMode: ebiten.ColorScaleModePremultipliedAlpha,
+		FillRule:       ebiten.FillRuleNonZero,
+		AntiAlias:      true,
+	})
+}
@@ -0,0 +1,89 @@
+package hbc
+
+import (
+	"encoding/xml"
+	"image"
+	_ "image/png"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// appMeta is the part of an app's meta.xml the grid shows. The Homebrew
+// Channel reads the same file.
+type appMeta struct {
+	Name             string `xml:"name"`
+	Coder            string `xml:"coder"`
+	Author           string `xml:"author"`
+	Version          string `xml:"version"`
+	ShortDescription string `xml:"short_description"`
+	LongDescription  string `xml:"long_description"`
+}
+
+// scanAppsDir reads an SD card style apps directory, where each app is a
+// folder with an optional icon.png and meta.xml next to its program. Folders
+// are listed in name order, as the channel does by default. An app whose files cannot be read is still listed under its
+// folder name.
+func scanAppsDir(dir string) ([]appTile, error) {
+	fsys := os.DirFS(dir)
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var tiles []appTile
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		tile := appTile{name: e.Name()}
+		if data, err := fs.ReadFile(fsys, path.Join(e.Name(), "meta.xml")); err == nil {
+			var meta appMeta
+			if err := xml.Unmarshal(data, &meta); err != nil {
+				log.Printf("Warning: Could not parse %s/meta.xml: %v\n", e.Name(), err)
+			} else {
+				tile.applyMeta(meta)
+			}
+		}
+		if f, err := fsys.Open(path.Join(e.Name(), "icon.png")); err == nil {
+			img, _, err := image.Decode(f)
+			f.Close()
+			if err != nil {
+				log.Printf("Warning: Could not decode %s/icon.png: %v\n", e.Name(), err)
+			} else {
+				tile.icon = ebiten.NewImageFromImage(img)
+			}
+		}
+		tiles = append(tiles, tile)
+	}
+	return tiles, nil
+}
+
+func (t *appTile) applyMeta(m appMeta) {
+	if name := strings.TrimSpace(m.Name); name != "" {
+		t.name = name
+	}
+	desc := strings.TrimSpace(m.ShortDescription)
+	if desc == "" {
+		// The first line of the long description is usually a summary.
+		desc, _, _ = strings.Cut(strings.TrimSpace(m.LongDescription), "\n")
+	}
+	t.description = desc
+
+	author := strings.TrimSpace(m.Coder)
+	if author == "" {
+		author = strings.TrimSpace(m.Author)
+	}
+	var about []string
+	if v := strings.TrimSpace(m.Version); v != "" {
+		about = append(about, v)
+	}
+	if author != "" {
+		about = append(about, "by "+author)
+	}
+	t.about = strings.Join(about, " ")
+}
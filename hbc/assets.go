@@ -0,0 +1,46 @@
+package hbc
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+
+	"golm/assets"
+)
+
+// DefaultAssets returns the assets compiled into the binary, rooted so that
+// textures live under img/ and audio under audio/.
+func DefaultAssets() fs.FS {
+	return assets.FS
+}
+
+// openAssets opens an asset pack: either a zip archive or a directory laid out
+// like the embedded assets (img/*.png, audio/*.wav). An empty path selects the
+// embedded assets. A pack may keep everything under a top-level assets/
+// directory.
+func openAssets(path string) (fs.FS, error) {
+	if path == "" {
+		return DefaultAssets(), nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fsys fs.FS
+	if info.IsDir() {
+		fsys = os.DirFS(path)
+	} else {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		fsys = zr
+	}
+
+	if sub, err := fs.Stat(fsys, "assets"); err == nil && sub.IsDir() {
+		return fs.Sub(fsys, "assets")
+	}
+	return fsys, nil
+}
@@ -0,0 +1,101 @@
+package hbc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var errChecksumMismatch = errors.New("checksum mismatch")
+
+// cachedAssetPack returns the path of the asset pack published at url,
+// downloading it into the user config directory first if it is not cached
+// yet. The pack is identified by its SHA-256, so a cached file is only reused
+// if it still matches.
+func cachedAssetPack(url, sum string) (string, error) {
+	sum = strings.ToLower(strings.TrimSpace(sum))
+	if len(sum) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid SHA-256 checksum %q", sum)
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "go-hbc-intro", "packs")
+	path := filepath.Join(dir, sum+".zip")
+
+	if err := verifyFile(path, sum); err == nil {
+		return path, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Warning: Discarding cached asset pack %s: %v\n", path, err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	log.Printf("Downloading asset pack from %s\n", url)
+	if err := download(url, path, sum); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func verifyFile(path, sum string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != sum {
+		return fmt.Errorf("%w: got %s", errChecksumMismatch, got)
+	}
+	return nil
+}
+
+// download fetches url into path, only moving the file into place once the
+// checksum has been verified.
+func download(url, path, sum string) error {
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "download-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), resp.Body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != sum {
+		return fmt.Errorf("%w: got %s", errChecksumMismatch, got)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
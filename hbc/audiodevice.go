@@ -0,0 +1,96 @@
+package hbc
+
+import (
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// Ebiten does not say when the music stops being played out, for example
+// when the output device is busy or goes away. The players just stop taking
+// samples, so the music's position stops moving while it still reports
+// playing. When that happens the players are recreated and put back where
+// the animation is.
+//
+// This only works around the stall. Ebiten binds the output device when the
+// audio context is created, and a process has just the one context, so the
+// new players play on the same device as the old ones. Playing on another
+// device, after headphones are unplugged or the display carrying HDMI audio
+// is switched, takes a restart.
+const (
+	// audioStallTime is how long the music may stand still before the
+	// output is taken to be gone. It is raised to twice the configured
+	// buffer, which the position moves in steps of.
+	audioStallTime = time.Second
+	// After each reopen that does not bring the music back, the next one
+	// waits twice as long, up to audioRetryMax.
+	audioRetryMax = 30 * time.Second
+)
+
+type audioWatch struct {
+	player *audio.Player
+	pos    time.Duration
+	// tick is the last tick watched. A gap, from a pause or a catch up,
+	// starts the watch over.
+	tick    int
+	stalled int
+	retry   time.Duration
+}
+
+// watchAudio reopens the players when the music has stalled.
+func (g *Intro) watchAudio() {
+	w := &g.audioWatch
+	if g.shuttingDown || g.silent || g.offline || g.party.player != nil {
+		w.player = nil
+		return
+	}
+	p := g.loopPlayer
+	if g.introPlayer != nil && g.introPlayer.IsPlaying() {
+		p = g.introPlayer
+	}
+	if p == nil || !p.IsPlaying() {
+		w.player = nil
+		return
+	}
+
+	pos := p.Position()
+	if p != w.player || pos != w.pos || g.ticks != w.tick+1 {
+		if p == w.player && pos != w.pos {
+			w.retry = 0
+		}
+		w.player, w.pos, w.tick, w.stalled = p, pos, g.ticks, 0
+		return
+	}
+	w.tick = g.ticks
+	w.stalled++
+
+	stall := max(audioStallTime, 2*time.Duration(g.cfg.AudioBuffer)*time.Millisecond)
+	if frameTime(w.stalled) < stall+w.retry {
+		return
+	}
+	log.Printf("Warning: The music stopped playing, recreating the music players\n")
+	g.reopenAudio()
+	w.player = nil
+	w.retry = min(max(2*w.retry, stall), audioRetryMax)
+}
+
+// reopenAudio replaces the music players with new ones at the same place, on
+// the same audio context and so on the same device.
+func (g *Intro) reopenAudio() {
+	for _, p := range []*audio.Player{g.introPlayer, g.loopPlayer} {
+		if p == nil {
+			continue
+		}
+		p.Pause()
+		if err := p.Close(); err != nil {
+			log.Printf("Warning: Could not close audio player: %v\n", err)
+		}
+	}
+	g.introPlayer, g.loopPlayer = nil, nil
+
+	g.initAudio(g.introStream, g.loopStream)
+	g.setVolume(g.baseVolume())
+	g.seekAudio()
+	g.updatePlayers()
+}
@@ -0,0 +1,69 @@
+package hbc
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"syscall/js"
+)
+
+// selectAudioDevice points the page's sound at another output with
+// AudioContext.setSinkId, where the browser has it. Ebiten creates its
+// AudioContext itself, so the constructor is wrapped to catch it. name is a
+// deviceId or label from navigator.mediaDevices.enumerateDevices(); browsers
+// only give labels to pages that were allowed to use a microphone or camera.
+func selectAudioDevice(name string) error {
+	ctor := js.Global().Get("AudioContext")
+	if ctor.IsUndefined() || ctor.Get("prototype").Get("setSinkId").IsUndefined() {
+		return errors.New("this browser cannot choose an audio device")
+	}
+	js.Global().Set("AudioContext", js.FuncOf(func(this js.Value, args []js.Value) any {
+		params := make([]any, len(args))
+		for i, a := range args {
+			params[i] = a
+		}
+		ctx := ctor.New(params...)
+		go setSinkID(ctx, name)
+		return ctx
+	}))
+	return nil
+}
+
+func setSinkID(ctx js.Value, name string) {
+	id := name
+	if devices, err := await(js.Global().Get("navigator").Get("mediaDevices").Call("enumerateDevices")); err == nil {
+		for i := range devices.Length() {
+			d := devices.Index(i)
+			if d.Get("kind").String() == "audiooutput" && d.Get("label").String() == name {
+				id = d.Get("deviceId").String()
+			}
+		}
+	}
+	if _, err := await(ctx.Call("setSinkId", id)); err != nil {
+		log.Printf("Warning: Could not select audio device %q: %v\n", name, err)
+	}
+}
+
+// await waits for a JavaScript promise. It must not be called from a
+// callback, which would block the event loop the promise needs.
+func await(promise js.Value) (js.Value, error) {
+	type result struct {
+		value js.Value
+		err   error
+	}
+	done := make(chan result, 1)
+	var then, catch js.Func
+	then = js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- result{value: args[0]}
+		return nil
+	})
+	catch = js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- result{err: fmt.Errorf("%s", args[0].Call("toString").String())}
+		return nil
+	})
+	defer then.Release()
+	defer catch.Release()
+	promise.Call("then", then, catch)
+	r := <-done
+	return r.value, r.err
+}
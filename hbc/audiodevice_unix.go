@@ -0,0 +1,21 @@
+//go:build !windows && !js
+
+package hbc
+
+import (
+	"errors"
+	"os"
+	"runtime"
+)
+
+// selectAudioDevice routes the intro's sound to a PulseAudio or PipeWire
+// sink, named as in "pactl list short sinks". The sound goes out through
+// ALSA's default device, which on most desktops is the sound server, and it
+// reads PULSE_SINK when the audio context opens. Other programs keep the
+// system default.
+func selectAudioDevice(name string) error {
+	if runtime.GOOS == "darwin" {
+		return errors.New("choosing an audio device is not available on macOS")
+	}
+	return os.Setenv("PULSE_SINK", name)
+}
@@ -0,0 +1,7 @@
+package hbc
+
+import "errors"
+
+func selectAudioDevice(name string) error {
+	return errors.New("choosing an audio device is not available on Windows")
+}
@@ -0,0 +1,193 @@
+package hbc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+	"github.com/mewkiz/flac"
+)
+
+// audioStream is decoded music as 16-bit stereo samples at sampleRate.
+type audioStream interface {
+	io.ReadSeeker
+	Length() int64
+}
+
+// audioExtensions are the music formats an asset pack may use, in the order
+// they are looked for. XM modules are looked for last only so that a pack
+// with one gets an error naming it rather than a missing WAV file.
+var audioExtensions = []string{".flac", ".mod", ".wav", ".xm"}
+
+// findAudio returns the path of the music stored under base, e.g.
+// "audio/intro", in whichever format the pack has it. Without any it returns
+// the WAV path, so that errors name the usual file.
+func findAudio(fsys fs.FS, base string) string {
+	for _, ext := range audioExtensions {
+		if fileExists(fsys, base+ext) {
+			return base + ext
+		}
+	}
+	return base + ".wav"
+}
+
+func decodeAudioFile(data io.Reader, p string) (audioStream, error) {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".flac":
+		return decodeFLAC(data)
+	case ".mod":
+		return decodeModule(data)
+	case ".xm":
+		return nil, errXM
+	}
+	return decodeWAV(data)
+}
+
+// loopedStream is music that loops over a part of its own rather than all
+// of it, such as a tracker module returning to its restart position. Both
+// are in bytes.
+type loopedStream interface {
+	Loop() (start, length int64)
+}
+
+type wavLoopStream struct {
+	*wav.Stream
+	start, length int64
+}
+
+func (s wavLoopStream) Loop() (int64, int64) {
+	return s.start, s.length
+}
+
+// decodeWAV decodes a WAV file at any sample rate, which the wav package
+// resamples itself, keeping the first loop of its smpl chunk if it has one.
+func decodeWAV(r io.Reader) (audioStream, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	s, err := wav.DecodeWithSampleRate(sampleRate, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, rate, ok := wavLoopPoints(data)
+	if !ok || rate == 0 {
+		return s, nil
+	}
+	// The points count sample frames in the file's own rate; the stream is
+	// 16-bit stereo at sampleRate.
+	toBytes := func(frame uint32) int64 {
+		return int64(frame) * sampleRate / int64(rate) * 4
+	}
+	loopStart, loopEnd := toBytes(start), min(toBytes(end+1), s.Length())
+	if loopStart >= loopEnd {
+		return s, nil
+	}
+	return wavLoopStream{s, loopStart, loopEnd - loopStart}, nil
+}
+
+// wavLoopPoints finds the first loop of the smpl chunk in a RIFF WAV file,
+// along with the sample rate from the fmt chunk. The end is inclusive.
+func wavLoopPoints(data []byte) (start, end, rate uint32, ok bool) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return 0, 0, 0, false
+	}
+	for p := 12; p+8 <= len(data); {
+		id := string(data[p : p+4])
+		// A chunk claiming to run past the end is cut short, which also keeps
+		// a huge size from overflowing int on 32-bit platforms.
+		size := int(min(binary.LittleEndian.Uint32(data[p+4:]), uint32(len(data)-p-8)))
+		body := data[p+8 : p+8+size]
+		switch {
+		case id == "fmt " && len(body) >= 8:
+			rate = binary.LittleEndian.Uint32(body[4:])
+		case id == "smpl" && len(body) >= 36+24 && binary.LittleEndian.Uint32(body[28:]) > 0:
+			start = binary.LittleEndian.Uint32(body[36+8:])
+			end = binary.LittleEndian.Uint32(body[36+12:])
+			ok = true
+		}
+		// Chunks are padded to an even size.
+		p += 8 + size + size%2
+	}
+	return start, end, rate, ok
+}
+
+// pcmStream is music decoded in full at load time.
+type pcmStream struct {
+	*bytes.Reader
+}
+
+func (s pcmStream) Length() int64 {
+	return s.Size()
+}
+
+// maxPreallocFrames is how many sample frames decodeFLAC makes room for up
+// front, two minutes of music. The count in the file's header is not
+// trusted beyond that; longer music grows the buffer as it is decoded.
+const maxPreallocFrames = 2 * 60 * sampleRate
+
+// decodeFLAC decodes a whole FLAC file to PCM. Mono files are played on both
+// channels, and channels past the second are dropped.
+func decodeFLAC(r io.Reader) (audioStream, error) {
+	stream, err := flac.New(r)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	info := stream.Info
+	shift := int(info.BitsPerSample) - 16
+
+	pcm := make([]byte, 0, min(info.NSamples, maxPreallocFrames)*4)
+	for {
+		frame, err := stream.ParseNext()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		left := frame.Subframes[0].Samples
+		right := left
+		if len(frame.Subframes) > 1 {
+			right = frame.Subframes[1].Samples
+		}
+		for i := range left {
+			pcm = binary.LittleEndian.AppendUint16(pcm, uint16(scaleSample(left[i], shift)))
+			pcm = binary.LittleEndian.AppendUint16(pcm, uint16(scaleSample(right[i], shift)))
+		}
+	}
+	return resample(pcm, int(info.SampleRate))
+}
+
+// resample converts decoded 16-bit stereo samples at the given rate to
+// sampleRate, so music does not have to be authored at 44.1 kHz.
+func resample(pcm []byte, from int) (audioStream, error) {
+	if from == sampleRate {
+		return pcmStream{bytes.NewReader(pcm)}, nil
+	}
+	if from <= 0 {
+		return nil, fmt.Errorf("invalid sample rate %d Hz", from)
+	}
+	out, err := io.ReadAll(audio.Resample(bytes.NewReader(pcm), int64(len(pcm)), from, sampleRate))
+	if err != nil {
+		return nil, err
+	}
+	return pcmStream{bytes.NewReader(out)}, nil
+}
+
+func scaleSample(v int32, shift int) int16 {
+	if shift < 0 {
+		return int16(v << -shift)
+	}
+	return int16(v >> shift)
+}
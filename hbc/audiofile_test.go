@@ -0,0 +1,121 @@
+package hbc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/mewkiz/flac/meta"
+)
+
+// testWAV builds a RIFF WAV file out of the given chunks, each an ID and
+// its body.
+func testWAV(chunks ...[2][]byte) []byte {
+	b := []byte("RIFF\x00\x00\x00\x00WAVE")
+	for _, c := range chunks {
+		b = append(b, c[0]...)
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(c[1])))
+		b = append(b, c[1]...)
+		if len(c[1])%2 == 1 {
+			b = append(b, 0)
+		}
+	}
+	binary.LittleEndian.PutUint32(b[4:], uint32(len(b)-8))
+	return b
+}
+
+// testSmpl is the body of a smpl chunk with a single loop.
+func testSmpl(start, end uint32) []byte {
+	b := make([]byte, 36+24)
+	binary.LittleEndian.PutUint32(b[28:], 1)
+	binary.LittleEndian.PutUint32(b[36+8:], start)
+	binary.LittleEndian.PutUint32(b[36+12:], end)
+	return b
+}
+
+func FuzzSmpl(f *testing.F) {
+	fmtChunk := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtChunk, 1)
+	binary.LittleEndian.PutUint16(fmtChunk[2:], 2)
+	binary.LittleEndian.PutUint32(fmtChunk[4:], sampleRate)
+
+	f.Add(testWAV([2][]byte{[]byte("fmt "), fmtChunk}, [2][]byte{[]byte("smpl"), testSmpl(100, 900)}))
+	f.Add(testWAV([2][]byte{[]byte("fmt "), fmtChunk}, [2][]byte{[]byte("data"), make([]byte, 8)}))
+	f.Add(testWAV([2][]byte{[]byte("smpl"), testSmpl(0, 0)[:40]}))
+	f.Add(testWAV([2][]byte{[]byte("LIST"), []byte("odd")}, [2][]byte{[]byte("smpl"), testSmpl(7, 3)}))
+	f.Add([]byte("RIFF\xff\xff\xff\xffWAVEsmpl\xff\xff\xff\xff"))
+	f.Add([]byte("RIFF"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		start, end, _, ok := wavLoopPoints(data)
+		if !ok && (start != 0 || end != 0) {
+			t.Errorf("no loop found, but got loop points %d and %d", start, end)
+		}
+	})
+}
+
+// testFLAC encodes a short ramp as a FLAC file, with nsamples written into
+// its header whether or not it is the real length.
+func testFLAC(tb testing.TB, channels frame.Channels, bits uint8, rate uint32, nsamples uint64) []byte {
+	tb.Helper()
+	const block = 256
+	info := &meta.StreamInfo{
+		BlockSizeMin:  block,
+		BlockSizeMax:  block,
+		SampleRate:    rate,
+		NChannels:     uint8(channels.Count()),
+		BitsPerSample: bits,
+		NSamples:      nsamples,
+	}
+	var buf bytes.Buffer
+	enc, err := flac.NewEncoder(&buf, info)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	f := &frame.Frame{Header: frame.Header{
+		HasFixedBlockSize: true,
+		BlockSize:         block,
+		SampleRate:        rate,
+		Channels:          channels,
+		BitsPerSample:     bits,
+	}}
+	for range channels.Count() {
+		samples := make([]int32, block)
+		for i := range samples {
+			samples[i] = int32(i-block/2) << (bits - 9)
+		}
+		f.Subframes = append(f.Subframes, &frame.Subframe{
+			SubHeader: frame.SubHeader{Pred: frame.PredVerbatim},
+			NSamples:  block,
+			Samples:   samples,
+		})
+	}
+	if err := enc.WriteFrame(f); err != nil {
+		tb.Fatal(err)
+	}
+	if err := enc.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func FuzzFLAC(f *testing.F) {
+	f.Add(testFLAC(f, frame.ChannelsLR, 16, sampleRate, 256))
+	f.Add(testFLAC(f, frame.ChannelsMono, 24, 48000, 256))
+	f.Add(testFLAC(f, frame.ChannelsLR, 8, 22050, 0))
+	// The header may claim far more samples than the file holds.
+	f.Add(testFLAC(f, frame.ChannelsLR, 16, sampleRate, 1<<36-1))
+	f.Add([]byte("fLaC"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s, err := decodeFLAC(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		if n := s.Length(); n%4 != 0 {
+			t.Errorf("decoded %d bytes, want whole 16-bit stereo frames", n)
+		}
+	})
+}
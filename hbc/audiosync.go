@@ -0,0 +1,47 @@
+package hbc
+
+import (
+	"math"
+	"time"
+)
+
+func frameTime(frames int) time.Duration {
+	return time.Duration(frames) * time.Second / 60
+}
+
+// audioLead is how many frames the music runs ahead of the animation. A
+// positive audio offset means the sound reaches the speakers late, as with
+// Bluetooth or some HDMI receivers, so the music has to start early.
+func (g *Intro) audioLead() int {
+	return int(math.Round(g.audioOffset.Seconds() * 60))
+}
+
+// updatePlayers starts the music at the frames it belongs to, shifted by the
+// audio offset. A lead beyond the first frame skips the start of the intro
+// jingle instead.
+func (g *Intro) updatePlayers() {
+	if g.shuttingDown || g.silent {
+		return
+	}
+	frame := g.count + g.audioLead()
+
+	if g.introPlayer != nil && frame >= 0 && !g.introPlayer.IsPlaying() {
+		if !g.introStarted {
+			g.introStarted = true
+			if frame > 0 {
+				g.introPlayer.SetPosition(frameTime(frame))
+			}
+		}
+		g.introPlayer.Play()
+	}
+
+	// The sped-up easter egg music stands in for the loop music while it
+	// plays.
+	loop := g.loopPlayer
+	if g.party.player != nil {
+		loop = g.party.player
+	}
+	if loop != nil && frame >= g.timeline.loopMusic && !loop.IsPlaying() {
+		loop.Play()
+	}
+}
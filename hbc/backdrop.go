@@ -0,0 +1,150 @@
+package hbc
+
+import (
+	"image/color"
+	"log"
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// A backdrop is an effect on the background layer that replaces or adds to
+// the plain sky. It is picked by name, so only one plays at a time.
+var (
+	backdropsMu sync.Mutex
+	backdrops   = map[string]func() Effect{
+		"starfield": func() Effect { return &starfield{} },
+	}
+)
+
+// RegisterBackdrop makes a backdrop available under name for the
+// "backdrop" config setting and the -backdrop flag. The effect should draw
+// on LayerBackground.
+func RegisterBackdrop(name string, newBackdrop func() Effect) {
+	backdropsMu.Lock()
+	defer backdropsMu.Unlock()
+	backdrops[name] = newBackdrop
+}
+
+// setupBackdrop adds the named backdrop. An empty name keeps the plain sky.
+func (g *Intro) setupBackdrop(name string) {
+	if name == "" {
+		return
+	}
+	backdropsMu.Lock()
+	newBackdrop, ok := backdrops[name]
+	backdropsMu.Unlock()
+	if !ok {
+		log.Printf("Warning: Unknown backdrop %q\n", name)
+		return
+	}
+	g.AddEffect(newBackdrop())
+}
+
+// The starfield is a slow parallax of stars above the waterline. It only
+// shows at night: the stars fade in as the palette's background darkens, so
+// they follow the theme key and SetPalette. Lengths are in the 810x456
+// layout.
+const (
+	starDotSize = 8
+	// stars closer to the water than starWaterMargin are hidden, so none
+	// sit on the surface.
+	starWaterMargin = 6
+)
+
+// starLayers go from the back to the front. Each is drawn at a depth for
+// the tilt parallax and drifts left by speed pixels a frame.
+var starLayers = []struct {
+	count       int
+	size, alpha float64
+	speed       float64
+	depth       float64
+}{
+	{count: 90, size: 1, alpha: 0.5, speed: 0.02, depth: 0.1},
+	{count: 45, size: 1.5, alpha: 0.75, speed: 0.05, depth: 0.3},
+	{count: 18, size: 2.2, alpha: 1, speed: 0.1, depth: 0.5},
+}
+
+type star struct {
+	x, y  float64
+	layer int
+	// phase and rate set how the star twinkles.
+	phase, rate float64
+}
+
+type starfield struct {
+	dot   *ebiten.Image
+	stars []star
+	frame float64
+}
+
+func (s *starfield) Init(in *Intro) error {
+	s.dot = ebiten.NewImage(starDotSize, starDotSize)
+	r := float32(starDotSize) / 2
+	vector.DrawFilledCircle(s.dot, r, r, r, color.White, true)
+
+	// The same sky every run, whatever the bubble seed.
+	rng := rand.New(rand.NewSource(7))
+	for i, l := range starLayers {
+		for range l.count {
+			s.stars = append(s.stars, star{
+				x:     rng.Float64() * in.width,
+				y:     rng.Float64() * in.height,
+				layer: i,
+				phase: rng.Float64() * 2 * math.Pi,
+				rate:  0.02 + rng.Float64()*0.04,
+			})
+		}
+	}
+	return nil
+}
+
+func (s *starfield) Update(in *Intro) error {
+	if !in.reducedMotion {
+		s.frame++
+	}
+	return nil
+}
+
+func (s *starfield) Draw(screen *ebiten.Image, in *Intro) {
+	night := nightness(in.currentPalette().Background)
+	if night == 0 {
+		return
+	}
+	water := in.waterLevel() - starWaterMargin
+	for _, st := range s.stars {
+		l := starLayers[st.layer]
+		px, py := in.parallaxOffset(l.depth)
+		y := st.y + py
+		if y > water {
+			continue
+		}
+		x := math.Mod(st.x-s.frame*l.speed+px, in.width)
+		if x < 0 {
+			x += in.width
+		}
+		twinkle := 0.75 + 0.25*math.Sin(s.frame*st.rate+st.phase)
+
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-starDotSize/2, -starDotSize/2)
+		op.GeoM.Scale(l.size/starDotSize, l.size/starDotSize)
+		op.GeoM.Translate(x, y)
+		op.ColorScale.ScaleAlpha(float32(l.alpha * twinkle * night))
+		op.Filter = ebiten.FilterLinear
+		in.drawImage(screen, s.dot, op)
+	}
+}
+
+func (s *starfield) Layer() Layer {
+	return LayerBackground
+}
+
+// nightness is how dark the sky is, from 0 for the day theme's white to 1
+// for the night theme's navy and anything darker.
+func nightness(c color.RGBA) float64 {
+	lum := (0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)) / 255
+	return min(max((0.5-lum)/0.4, 0), 1)
+}
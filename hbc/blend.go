@@ -0,0 +1,48 @@
+package hbc
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Ebiten keeps images with premultiplied alpha, so the blend factors below
+// take the source color as is rather than scaling it by the source alpha
+// again.
+var blendModes = map[string]ebiten.Blend{
+	"normal": ebiten.BlendSourceOver,
+	"add":    ebiten.BlendLighter,
+	"multiply": {
+		BlendFactorSourceRGB:        ebiten.BlendFactorDestinationColor,
+		BlendFactorSourceAlpha:      ebiten.BlendFactorOne,
+		BlendFactorDestinationRGB:   ebiten.BlendFactorOneMinusSourceAlpha,
+		BlendFactorDestinationAlpha: ebiten.BlendFactorOneMinusSourceAlpha,
+		BlendOperationRGB:           ebiten.BlendOperationAdd,
+		BlendOperationAlpha:         ebiten.BlendOperationAdd,
+	},
+	"screen": {
+		BlendFactorSourceRGB:        ebiten.BlendFactorOne,
+		BlendFactorSourceAlpha:      ebiten.BlendFactorOne,
+		BlendFactorDestinationRGB:   ebiten.BlendFactorOneMinusSourceColor,
+		BlendFactorDestinationAlpha: ebiten.BlendFactorOneMinusSourceAlpha,
+		BlendOperationRGB:           ebiten.BlendOperationAdd,
+		BlendOperationAlpha:         ebiten.BlendOperationAdd,
+	},
+}
+
+func (g *Intro) setupBlends() {
+	g.layerBlends = make(map[Layer]ebiten.Blend)
+	for name, mode := range g.cfg.Blend {
+		layer, ok := layerByName(name)
+		if !ok || layer.overlay() {
+			log.Printf("Warning: Unknown animation layer %q in blend settings\n", name)
+			continue
+		}
+		blend, ok := blendModes[mode]
+		if !ok {
+			log.Printf("Warning: Unknown blend mode %q for layer %s\n", mode, name)
+			continue
+		}
+		g.layerBlends[layer] = blend
+	}
+}
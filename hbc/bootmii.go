@@ -0,0 +1,172 @@
+package hbc
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// The bootmii variant is a console boot screen: a log types itself out and
+// scrolls during the opening, then the loop shows a text menu whose
+// selection moves on every bootMenuFrames, all behind scanlines.
+
+const (
+	bootMargin     = 28
+	bootLineHeight = 18
+	bootFontSize   = 14
+	// Each log line starts bootLineFrames after the previous one and types
+	// bootTypeSpeed characters a frame.
+	bootLogStart   = 20
+	bootLineFrames = 11
+	bootTypeSpeed  = 3
+	// bootMenuFrames divides the loop, so the selection is where it was
+	// when the loop wraps around.
+	bootMenuFrames = (loopEnd - loopStart) / 3
+	bootFadeFrames = 30
+)
+
+var (
+	bootText      = color.RGBA{200, 200, 200, 255}
+	bootDim       = color.RGBA{110, 110, 110, 255}
+	bootHighlight = color.RGBA{230, 230, 230, 255}
+	bootScanline  = color.RGBA{0, 0, 0, 90}
+)
+
+var bootLog = []string{
+	"BootMii v1.0",
+	"",
+	"Initializing Starlet ...................... OK",
+	"Detecting hardware ........................ Hollywood rev 11",
+	"Testing MEM1 0x00000000-0x017fffff ........ OK",
+	"Testing MEM2 0x10000000-0x13ffffff ........ OK",
+	"Enabling caches ........................... OK",
+	"Starting exception vectors ................ OK",
+	"Mounting SD card .......................... OK",
+	"  FAT32, 1953 MiB free",
+	"Reading /bootmii/bootmii.ini .............. OK",
+	"  VIDEO=NTSC  AUTOBOOT=OFF  BOOTDELAY=5",
+	"Checking NAND ............................. 512 MiB",
+	"  bad blocks: 0",
+	"  boot1 ................................... hash OK",
+	"  boot2 ................................... signature OK",
+	"Loading IOS58 ............................. OK",
+	"Loading IOS36 ............................. skipped",
+	"Scanning SD card for applications",
+	"  /apps/homebrew_browser",
+	"  /apps/wiixplorer",
+	"  /apps/savegame_manager",
+	"  /apps/go-hbc-intro",
+	"Initializing USB .......................... OK",
+	"Initializing Bluetooth .................... OK",
+	"Syncing remotes ........................... 1 found",
+	"Setting video mode ........................ 640x480i",
+	"Loading menu graphics ..................... OK",
+	"",
+	"Starting BootMii menu",
+}
+
+var bootMenu = []string{"Launch System Menu", "Launch Homebrew Channel", "Options"}
+
+func (g *Intro) setupBootMiiLayers() {
+	g.addLayer(LayerBackground, g.drawBootBackground)
+	g.addLayer(LayerTitle, g.drawBootScreen)
+	g.addLayer(LayerFlash, g.drawScanlines)
+}
+
+func (g *Intro) drawBootBackground(screen *ebiten.Image) {
+	screen.Fill(color.Black)
+}
+
+func (g *Intro) drawBootScreen(screen *ebiten.Image) {
+	if g.count < loopStart {
+		g.drawBootLog(screen)
+	} else {
+		g.drawBootMenu(screen)
+	}
+}
+
+// drawBootLog shows the lines typed so far, scrolled so the newest one is
+// at the bottom once the screen is full.
+func (g *Intro) drawBootLog(screen *ebiten.Image) {
+	frame := g.count - bootLogStart
+	if frame < 0 {
+		return
+	}
+	typed := min(frame/bootLineFrames+1, len(bootLog))
+	rows := int((g.height - 2*bootMargin) / bootLineHeight)
+	first := max(typed-rows, 0)
+
+	face := monoFace(bootFontSize)
+	for i := first; i < typed; i++ {
+		line := bootLog[i]
+		if i == typed-1 {
+			n := (frame - i*bootLineFrames) * bootTypeSpeed
+			line = line[:min(n, len(line))]
+			if g.count/15%2 == 0 {
+				line += "_"
+			}
+		}
+		y := bootMargin + float64(i-first)*bootLineHeight
+		g.drawBootText(screen, line, face, bootMargin, y, bootText, 1)
+	}
+}
+
+// drawBootMenu shows the menu the log ends in. It fades in once, when the
+// loop is first reached.
+func (g *Intro) drawBootMenu(screen *ebiten.Image) {
+	alpha := 1.0
+	if g.loopCount == 0 {
+		alpha = min(float64(g.count-loopStart)/bootFadeFrames, 1)
+	}
+
+	title := monoFace(44)
+	w, _ := text.Measure("BootMii", title, 0)
+	g.drawBootText(screen, "BootMii", title, (g.width-w)/2, g.height/2-150, bootHighlight, alpha)
+
+	face := monoFace(bootFontSize + 2)
+	selected := (g.count - loopStart) / bootMenuFrames % len(bootMenu)
+	for i, item := range bootMenu {
+		label := "  " + item + "  "
+		w, _ := text.Measure(label, face, 0)
+		x, y := (g.width-w)/2, g.height/2-30+float64(i)*32
+		clr := bootText
+		if i == selected {
+			g.fillBootRect(screen, x-6, y-4, w+12, bootLineHeight+10, bootHighlight, alpha)
+			clr = color.RGBA{0, 0, 0, 255}
+		}
+		g.drawBootText(screen, label, face, x, y, clr, alpha)
+	}
+
+	hint := "Use the power and reset buttons to choose"
+	if g.count/30%2 == 0 {
+		hint += " _"
+	}
+	g.drawBootText(screen, hint, face, bootMargin, g.height-bootMargin-bootLineHeight, bootDim, alpha)
+}
+
+func (g *Intro) drawBootText(screen *ebiten.Image, s string, face *text.GoTextFace, x, y float64, clr color.RGBA, alpha float64) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x, y)
+	op.GeoM.Concat(g.view)
+	op.ColorScale.ScaleWithColor(clr)
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	op.Blend = g.blend
+	text.Draw(screen, s, face, op)
+}
+
+func (g *Intro) fillBootRect(screen *ebiten.Image, x, y, w, h float64, clr color.RGBA, alpha float64) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(w, h)
+	op.GeoM.Translate(x, y)
+	op.ColorScale.ScaleWithColor(clr)
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	g.drawImage(screen, solidSource(), op)
+}
+
+// drawScanlines darkens every third line of the layout, like a CRT.
+func (g *Intro) drawScanlines(screen *ebiten.Image) {
+	for y := 0.0; y < g.height; y += 3 {
+		g.fillBootRect(screen, 0, y, g.width, 1, bootScanline, 1)
+	}
+}
@@ -0,0 +1,67 @@
+package hbc
+
+import "testing"
+
+func BenchmarkGenerateBubbles(b *testing.B) {
+	g := newTestIntro(b, 42)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		g.generateBubbles()
+	}
+}
+
+func BenchmarkChooseBubbleType(b *testing.B) {
+	g := newTestIntro(b, 42)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		g.chooseBubbleType()
+	}
+}
+
+// BenchmarkBubbleSprite works out one frame of the per-bubble transform and
+// fade, as drawBubbles does, over every scripted bubble.
+func BenchmarkBubbleSprite(b *testing.B) {
+	g := newTestIntro(b, 42)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		frame := loopStart + i%(loopEnd-loopStart)
+		for _, bubble := range g.bubbles {
+			g.bubbleSprite(bubble, frame)
+		}
+	}
+}
+
+func BenchmarkSimulateBubbles(b *testing.B) {
+	g := newTestIntro(b, 42)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		g.simulateBubbles(loopStart + i%(loopEnd-loopStart))
+	}
+}
+
+// TestBubbleHotPathsDoNotAllocate keeps the paths run for every bubble in
+// every frame free of allocations.
+func TestBubbleHotPathsDoNotAllocate(t *testing.T) {
+	g := newTestIntro(t, 42)
+	frame := loopStart
+	for _, c := range []struct {
+		name string
+		run  func()
+	}{
+		{"chooseBubbleType", func() { g.chooseBubbleType() }},
+		{"bubbleSprite", func() {
+			for _, bubble := range g.bubbles {
+				g.bubbleSprite(bubble, frame)
+			}
+			frame++
+		}},
+	} {
+		if n := testing.AllocsPerRun(100, c.run); n > 0 {
+			t.Errorf("%s makes %.1f allocations per call, want none", c.name, n)
+		}
+	}
+}
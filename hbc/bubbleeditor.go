@@ -0,0 +1,360 @@
+package hbc
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// The bubble editor plays the intro over a timeline of its scripted
+// bubbles. Clicking the timeline moves to a frame; clicking the scene adds
+// a burst there, or in emitter mode dragging across it adds an emitter.
+// Every change regenerates the bubbles from the same seed, so the random
+// ones stay where they are and the new ones show at once.
+const (
+	editorMargin         = 20
+	editorTimelineHeight = 14
+	// editorMarkerY is how far above the bottom the markers of the bursts
+	// and emitters starting now are drawn.
+	editorMarkerY       = 64
+	editorEmitterFrames = 180
+	editorMinWidth      = 40
+	// editorPickRange is how close a right click has to be to remove a
+	// burst or an emitter.
+	editorPickRange     = 60
+	editorMessageFrames = 3 * 60
+)
+
+var (
+	editorBurstColor   = color.RGBA{52, 190, 237, 255}
+	editorEmitterColor = color.RGBA{240, 150, 40, 255}
+	editorTrackColor   = color.RGBA{40, 40, 40, 200}
+	editorLoopColor    = color.RGBA{80, 80, 80, 200}
+)
+
+type editorMode int
+
+const (
+	editBursts editorMode = iota
+	editEmitters
+)
+
+type bubbleEditor struct {
+	game *Intro
+	path string
+
+	frame   int
+	playing bool
+	mode    editorMode
+	// count and rate are given to new bursts and emitters.
+	count int
+	rate  float64
+
+	scrubbing bool
+	dragging  bool
+	dragX     float64
+
+	dirty         bool
+	quitArmed     bool
+	message       string
+	messageFrames int
+}
+
+func newBubbleEditor(g *Intro, path string) *bubbleEditor {
+	g.prepareOffline()
+	return &bubbleEditor{
+		game:  g,
+		path:  path,
+		frame: g.timeline.flash,
+		count: defaultBurstCount,
+		rate:  4,
+	}
+}
+
+func (e *bubbleEditor) drawsThroughView() {}
+
+func (e *bubbleEditor) timelineRect() (x, y, w, h float64) {
+	g := e.game
+	return editorMargin, g.height - editorMargin - editorTimelineHeight, g.width - 2*editorMargin, editorTimelineHeight
+}
+
+func (e *bubbleEditor) frameX(frame int) float64 {
+	x, _, w, _ := e.timelineRect()
+	return x + float64(frame)/loopEnd*w
+}
+
+func (e *bubbleEditor) frameAt(x float64) int {
+	tx, _, tw, _ := e.timelineRect()
+	return min(max(int((x-tx)/tw*loopEnd), 0), loopEnd-1)
+}
+
+func (e *bubbleEditor) onTimeline(y float64) bool {
+	_, ty, _, th := e.timelineRect()
+	return y >= ty-6 && y <= ty+th+6
+}
+
+func (e *bubbleEditor) say(format string, args ...any) {
+	e.message = fmt.Sprintf(format, args...)
+	e.messageFrames = editorMessageFrames
+}
+
+func (e *bubbleEditor) Update() error {
+	g := e.game
+	if g.quitRequested.Load() || ebiten.IsWindowBeingClosed() {
+		return ebiten.Termination
+	}
+	if g.input.JustPressed(ActionQuit) {
+		if !e.dirty || e.quitArmed {
+			return ebiten.Termination
+		}
+		e.quitArmed = true
+		e.say("Unsaved changes: S saves, Esc again quits")
+	}
+
+	e.updateKeys()
+	e.updateMouse()
+
+	if e.playing {
+		if e.frame++; e.frame >= loopEnd {
+			e.frame = loopStart
+		}
+	}
+	g.count = e.frame
+	g.ticks++
+	if e.messageFrames > 0 {
+		e.messageFrames--
+	}
+	return nil
+}
+
+func (e *bubbleEditor) updateKeys() {
+	step := 1
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		step = 10
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		e.playing = !e.playing
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight):
+		e.playing = false
+		e.frame = min(e.frame+step, loopEnd-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		e.playing = false
+		e.frame = max(e.frame-step, 0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		e.mode = 1 - e.mode
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		if e.mode == editBursts {
+			e.count = min(e.count+2, maxBurstCount)
+		} else {
+			e.rate = min(e.rate+1, maxEmitterRate)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		if e.mode == editBursts {
+			e.count = max(e.count-2, 1)
+		} else {
+			e.rate = max(e.rate-1, 1)
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyS):
+		e.save()
+	}
+}
+
+func (e *bubbleEditor) updateMouse() {
+	g := e.game
+	x, y := g.cursorPosition()
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		switch {
+		case e.onTimeline(y):
+			e.scrubbing = true
+			e.playing = false
+		case e.mode == editBursts:
+			g.cfg.Bubbles.Bursts = append(g.cfg.Bubbles.Bursts, BurstConfig{
+				Frame: e.frame,
+				X:     math.Round(x - g.width/2),
+				Count: e.count,
+			})
+			e.changed()
+		default:
+			e.dragging, e.dragX = true, x
+		}
+	}
+	if e.scrubbing {
+		e.frame = e.frameAt(x)
+		if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+			e.scrubbing = false
+		}
+	}
+	if e.dragging && !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		e.dragging = false
+		center, width := (e.dragX+x)/2, math.Abs(x-e.dragX)
+		g.cfg.Bubbles.Emitters = append(g.cfg.Bubbles.Emitters, EmitterConfig{
+			X:     math.Round(center - g.width/2),
+			Width: math.Round(max(width, editorMinWidth)),
+			From:  e.frame,
+			To:    min(e.frame+editorEmitterFrames, loopEnd),
+			Rate:  e.rate,
+		})
+		e.changed()
+	}
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) && !e.onTimeline(y) {
+		e.removeNearest(x - g.width/2)
+	}
+}
+
+// removeNearest removes the burst or emitter closest to x around the
+// current frame. Frames count a quarter as much as pixels.
+func (e *bubbleEditor) removeNearest(x float64) {
+	c := &e.game.cfg.Bubbles
+	best, burst, emitter := float64(editorPickRange), -1, -1
+	for i, b := range c.Bursts {
+		d := math.Abs(b.X-x) + math.Abs(float64(e.frame-b.Frame))/4
+		if d < best {
+			best, burst, emitter = d, i, -1
+		}
+	}
+	for i, em := range c.Emitters {
+		dx := max(math.Abs(em.X-x)-em.Width/2, 0)
+		df := max(float64(em.From-e.frame), float64(e.frame-em.To), 0)
+		if d := dx + df/4; d < best {
+			best, burst, emitter = d, -1, i
+		}
+	}
+	switch {
+	case burst >= 0:
+		c.Bursts = append(c.Bursts[:burst], c.Bursts[burst+1:]...)
+	case emitter >= 0:
+		c.Emitters = append(c.Emitters[:emitter], c.Emitters[emitter+1:]...)
+	default:
+		return
+	}
+	e.changed()
+}
+
+func (e *bubbleEditor) changed() {
+	e.dirty = true
+	e.quitArmed = false
+	e.game.regenerateBubbles()
+}
+
+func (e *bubbleEditor) save() {
+	c := e.game.cfg.Bubbles
+	if err := saveSpawns(e.path, c.Bursts, c.Emitters); err != nil {
+		e.say("Could not save %s: %v", e.path, err)
+		return
+	}
+	e.dirty = false
+	e.say("Saved %d bursts and %d emitters to %s", len(c.Bursts), len(c.Emitters), e.path)
+}
+
+// saveSpawns writes the bursts and emitters into the bubbles section of the
+// config file at path, keeping every other setting in it.
+func saveSpawns(path string, bursts []BurstConfig, emitters []EmitterConfig) error {
+	return updateBubbleConfig(path, func(bubbles map[string]json.RawMessage) error {
+		if err := setConfigKey(bubbles, "bursts", bursts); err != nil {
+			return err
+		}
+		return setConfigKey(bubbles, "emitters", emitters)
+	})
+}
+
+// updateBubbleConfig is updateConfigFile for the settings in the bubbles
+// section.
+func updateBubbleConfig(path string, edit func(bubbles map[string]json.RawMessage) error) error {
+	return updateConfigFile(path, func(doc map[string]json.RawMessage) error {
+		bubbles := map[string]json.RawMessage{}
+		if raw, ok := doc["bubbles"]; ok {
+			if err := json.Unmarshal(raw, &bubbles); err != nil {
+				return err
+			}
+		}
+		if err := edit(bubbles); err != nil {
+			return err
+		}
+		return setConfigKey(doc, "bubbles", bubbles)
+	})
+}
+
+func (e *bubbleEditor) Draw(screen *ebiten.Image) {
+	g := e.game
+	g.drawWorld(screen)
+
+	ui := g.uiLayer(screen)
+	e.drawMarkers(ui)
+	e.drawTimeline(ui)
+	e.drawStatus(ui)
+	g.flushUILayer(screen, ui)
+}
+
+// drawMarkers shows where the bursts and emitters releasing bubbles in
+// this frame are.
+func (e *bubbleEditor) drawMarkers(ui *ebiten.Image) {
+	g := e.game
+	y := float32(g.height - editorMarkerY)
+	for _, b := range g.cfg.Bubbles.Bursts {
+		spread := b.Spread
+		if spread <= 0 {
+			spread = defaultBurstSpread
+		}
+		if e.frame >= b.Frame && e.frame < b.Frame+spread {
+			vector.DrawFilledCircle(ui, float32(g.width/2+b.X), y, 6, editorBurstColor, true)
+		}
+	}
+	for _, em := range g.cfg.Bubbles.Emitters {
+		if e.frame >= em.From && e.frame < em.To {
+			x := float32(g.width/2 + em.X - em.Width/2)
+			vector.DrawFilledRect(ui, x, y-3, float32(em.Width), 6, editorEmitterColor, true)
+		}
+	}
+	if e.dragging {
+		cx, _ := g.cursorPosition()
+		x0, x1 := min(e.dragX, cx), max(e.dragX, cx)
+		vector.StrokeRect(ui, float32(x0), y-3, float32(x1-x0), 6, 1, editorEmitterColor, true)
+	}
+}
+
+func (e *bubbleEditor) drawTimeline(ui *ebiten.Image) {
+	x, y, w, h := e.timelineRect()
+	fx, fy, fw, fh := float32(x), float32(y), float32(w), float32(h)
+	vector.DrawFilledRect(ui, fx, fy, fw, fh, editorTrackColor, false)
+	// The part that repeats.
+	loopX := float32(e.frameX(loopStart))
+	vector.DrawFilledRect(ui, loopX, fy, fx+fw-loopX, fh, editorLoopColor, false)
+
+	c := e.game.cfg.Bubbles
+	for _, em := range c.Emitters {
+		x0, x1 := float32(e.frameX(em.From)), float32(e.frameX(em.To))
+		vector.DrawFilledRect(ui, x0, fy+fh/2, x1-x0, fh/2, editorEmitterColor, false)
+	}
+	for _, b := range c.Bursts {
+		bx := float32(e.frameX(b.Frame))
+		vector.DrawFilledRect(ui, bx-1, fy, 2, fh/2, editorBurstColor, false)
+	}
+	px := float32(e.frameX(e.frame))
+	vector.StrokeLine(ui, px, fy-4, px, fy+fh+4, 2, color.White, false)
+}
+
+func (e *bubbleEditor) drawStatus(ui *ebiten.Image) {
+	brush := fmt.Sprintf("Bursts: click to add %d bubbles", e.count)
+	if e.mode == editEmitters {
+		brush = fmt.Sprintf("Emitters: drag to add %.0f bubbles/s", e.rate)
+	}
+	saved := ""
+	if e.dirty {
+		saved = "  (unsaved)"
+	}
+	status := fmt.Sprintf("Frame %d/%d  %s%s\nSpace: play  Left/Right: step  Tab: mode  Up/Down: amount\nRight click: remove  S: save to %s  Esc: quit",
+		e.frame, loopEnd, brush, saved, e.path)
+	if e.messageFrames > 0 {
+		status += "\n\n" + e.message
+	}
+	ebitenutil.DebugPrintAt(ui, status, 8, 8)
+}
@@ -0,0 +1,72 @@
+package hbc
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// cursorPosition returns the mouse cursor in 810x456 layout coordinates. The
+// cursor is reported in the size Layout returned, which is either the render
+// size or, with integer scaling, the window in device pixels.
+func (g *Intro) cursorPosition() (float64, float64) {
+	cx, cy := ebiten.CursorPosition()
+	x, y := float64(cx), float64(cy)
+	if g.cfg.IntegerScale {
+		ww, wh := ebiten.WindowSize()
+		s := ebiten.Monitor().DeviceScaleFactor()
+		sw, sh := int(float64(ww)*s), int(float64(wh)*s)
+		w, h := g.renderSize()
+		scale := max(min(sw/w, sh/h), 1)
+		x = (x - float64((sw-w*scale)/2)) / float64(scale)
+		y = (y - float64((sh-h*scale)/2)) / float64(scale)
+	}
+	return x/g.renderScale + g.viewport.x, y/g.renderScale + g.viewport.y
+}
+
+// bubbleHit is a bubble found under a point, in either g.bubbles or
+// g.liveBubbles.
+type bubbleHit struct {
+	live  bool
+	index int
+	// size is the bubble's diameter.
+	size float64
+}
+
+// bubbleAt finds the frontmost bubble covering x, y in layout coordinates.
+// Live bubbles are drawn last, so they are checked first.
+func (g *Intro) bubbleAt(x, y float64) (bubbleHit, bool) {
+	for i := len(g.liveBubbles) - 1; i >= 0; i-- {
+		if size, ok := g.bubbleCovers(g.liveBubbles[i], g.ticks, x, y); ok {
+			return bubbleHit{live: true, index: i, size: size}, true
+		}
+	}
+	for i := len(g.bubbles) - 1; i >= 0; i-- {
+		if size, ok := g.bubbleCovers(g.bubbles[i], g.count, x, y); ok {
+			return bubbleHit{index: i, size: size}, true
+		}
+	}
+	return bubbleHit{}, false
+}
+
+func (g *Intro) bubbleCovers(b Bubble, frame int, x, y float64) (float64, bool) {
+	bx, by, _, ok := bubblePosition(b, frame)
+	if !ok || b.popped == g.loopCount+1 {
+		return 0, false
+	}
+	bt := g.bubbleTypes[b.typeID]
+	return bt.width, math.Hypot(x-(g.width/2+bx), y-by) <= bt.width/2
+}
+
+// popBubble removes a bubble with a pop. Live bubbles end right away;
+// scripted ones stay away until the loop comes around again.
+func (g *Intro) popBubble(hit bubbleHit) {
+	if hit.live {
+		// pruneLiveBubbles plays the pop.
+		g.liveBubbles[hit.index].end = g.ticks
+		return
+	}
+	b := &g.bubbles[hit.index]
+	b.popped = g.loopCount + 1
+	g.playSound(popSound, g.bubblePan(*b))
+}
@@ -0,0 +1,173 @@
+package hbc
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+)
+
+// A bubble layout freezes the bubbles a seed generated, so a nice one can be
+// kept and shared however the random generation changes. Every bubble rises
+// the same way from below the bottom edge, so only where and when it rises
+// is saved. Bubble types are saved by texture name.
+type bubbleLayout struct {
+	// Seed is the seed the layout was generated from, for reference.
+	Seed    int64          `json:"seed"`
+	Bubbles []layoutBubble `json:"bubbles"`
+}
+
+type layoutBubble struct {
+	Type     string  `json:"type"`
+	X        float64 `json:"x"`
+	Rotation float64 `json:"rotation"`
+	Start    int     `json:"start"`
+	End      int     `json:"end"`
+}
+
+// exportLayout is the layout of the generated bubbles. The copies that
+// generateBubbles adds to carry bubbles across the loop's seam end after the
+// loop, and are left out.
+func (g *Intro) exportLayout() bubbleLayout {
+	l := bubbleLayout{Seed: g.seed}
+	for _, b := range g.bubbles {
+		if b.end > loopEnd {
+			continue
+		}
+		l.Bubbles = append(l.Bubbles, layoutBubble{
+			Type:     g.bubbleTypes[b.typeID].name,
+			X:        b.x,
+			Rotation: b.rotation,
+			Start:    b.start,
+			End:      b.end,
+		})
+	}
+	return l
+}
+
+func saveLayout(path string, l bubbleLayout) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// readLayout reads the layout at path, checking it against the intro's
+// bubble types.
+func (g *Intro) readLayout(path string) (*bubbleLayout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var l bubbleLayout
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, err
+	}
+	types := map[string]bool{}
+	for _, bt := range g.bubbleTypes {
+		types[bt.name] = true
+	}
+	for i, b := range l.Bubbles {
+		if !types[b.Type] {
+			return nil, fmt.Errorf("bubble %d has type %q, which this config does not have", i, b.Type)
+		}
+		if b.Start < 0 || b.End <= b.Start || b.End > loopEnd {
+			return nil, fmt.Errorf("bubble %d rises from frame %d to %d, want 0 to %d", i, b.Start, b.End, loopEnd)
+		}
+	}
+	return &l, nil
+}
+
+// setupBubbleLayout loads the bubble layout named in the config, which then
+// replaces the random bubbles.
+func (g *Intro) setupBubbleLayout(path string) {
+	if path == "" {
+		return
+	}
+	l, err := g.readLayout(path)
+	if err != nil {
+		log.Printf("Warning: Could not load bubble layout %s: %v\n", path, err)
+		return
+	}
+	g.layout = l
+}
+
+// layoutBubbles places the bubbles of the loaded layout.
+func (g *Intro) layoutBubbles() []Bubble {
+	index := map[string]int{}
+	for i, bt := range g.bubbleTypes {
+		index[bt.name] = i
+	}
+	// As in newBubble, bubbles start well below the bottom edge.
+	yStart := screenWidth + g.height - screenHeight
+	bubbles := make([]Bubble, 0, len(g.layout.Bubbles))
+	for _, lb := range g.layout.Bubbles {
+		bubbles = append(bubbles, Bubble{
+			typeID:   index[lb.Type],
+			x:        lb.X,
+			y:        yStart,
+			startX:   lb.X,
+			startY:   yStart,
+			endY:     170,
+			scale:    1.0,
+			rotation: lb.Rotation,
+			start:    lb.Start,
+			end:      lb.End,
+			length:   lb.End - lb.Start,
+		})
+	}
+	return bubbles
+}
+
+func runExportBubbles(args []string) error {
+	fset, common := newFlagSet("export-bubbles")
+	out := fset.String("o", "bubbles.json", "layout file to write")
+	fset.Parse(args)
+
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	l := g.exportLayout()
+	if err := saveLayout(*out, l); err != nil {
+		return fmt.Errorf("could not save bubble layout %s: %w", *out, err)
+	}
+	fmt.Printf("Saved %d bubbles from seed %d to %s\n", len(l.Bubbles), l.Seed, *out)
+	return nil
+}
+
+// runImportBubbles checks a layout file and names it in the config file, so
+// every later run plays it.
+func runImportBubbles(args []string) error {
+	fset, common := newFlagSet("import-bubbles")
+	in := fset.String("layout", "bubbles.json", "layout file saved by export-bubbles")
+	out := fset.String("o", "", "config file to add the layout to (defaults to -config, or config.json)")
+	fset.Parse(args)
+	layout := *in
+
+	path := *out
+	if path == "" {
+		path = common.config
+	}
+	if path == "" {
+		path = "config.json"
+	}
+
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	l, err := g.readLayout(layout)
+	if err != nil {
+		return fmt.Errorf("could not load bubble layout %s: %w", layout, err)
+	}
+	err = updateBubbleConfig(path, func(bubbles map[string]json.RawMessage) error {
+		return setConfigKey(bubbles, "layout", layout)
+	})
+	if err != nil {
+		return fmt.Errorf("could not save %s: %w", path, err)
+	}
+	fmt.Printf("%s now plays the %d bubbles in %s\n", path, len(l.Bubbles), layout)
+	return nil
+}
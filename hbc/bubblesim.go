@@ -0,0 +1,89 @@
+package hbc
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// bubbleChunk is the fewest bubbles a worker is given. Below that the
+// scripted bubbles are worked out on the calling goroutine, as starting
+// workers would cost more than it saves.
+const bubbleChunk = 2048
+
+// bubbleSprite is where a bubble is drawn in one frame.
+type bubbleSprite struct {
+	x, y     float64
+	rotation float64
+	alpha    float64
+	visible  bool
+}
+
+// bubbleAlpha fades a bubble in over the first tenth of its rise and out
+// over the last three tenths.
+func bubbleAlpha(progress float64) float64 {
+	const fadePoint = 0.7
+	var alpha float64
+	if progress < 0.1 {
+		alpha = progress * 10.0
+	} else if progress > fadePoint {
+		alpha = 1.0 - (progress-fadePoint)/(1.0-fadePoint)
+	} else {
+		alpha = 1.0
+	}
+	return min(max(alpha, 0), 1)
+}
+
+// bubbleSprite works out a bubble for frame. It only reads g, so it is safe
+// to call from several goroutines at once.
+func (g *Intro) bubbleSprite(bubble Bubble, frame int) bubbleSprite {
+	x, y, progress, ok := bubblePosition(bubble, frame)
+	if !ok || bubble.popped == g.loopCount+1 {
+		return bubbleSprite{}
+	}
+	return bubbleSprite{
+		x:        g.width/2 + x,
+		y:        y,
+		rotation: bubble.rotation + progress*math.Pi*2*0.5,
+		alpha:    bubbleAlpha(progress),
+		visible:  true,
+	}
+}
+
+// simulateBubbles fills g.bubbleSprites with the scripted bubbles for frame,
+// splitting them between up to GOMAXPROCS workers. Each worker writes its
+// own part of the buffer, which is kept between frames.
+func (g *Intro) simulateBubbles(frame int) []bubbleSprite {
+	n := len(g.bubbles)
+	if cap(g.bubbleSprites) < n {
+		g.bubbleSprites = make([]bubbleSprite, n)
+	}
+	sprites := g.bubbleSprites[:n]
+
+	fill := func(from, to int) {
+		for i := from; i < to; i++ {
+			if g.bubbleShown(i) {
+				sprites[i] = g.bubbleSprite(g.bubbles[i], frame)
+			} else {
+				sprites[i] = bubbleSprite{}
+			}
+		}
+	}
+
+	workers := min(runtime.GOMAXPROCS(0), n/bubbleChunk)
+	if workers < 2 {
+		fill(0, n)
+		return sprites
+	}
+	var wg sync.WaitGroup
+	step := (n + workers - 1) / workers
+	for from := 0; from < n; from += step {
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			fill(from, to)
+		}(from, min(from+step, n))
+	}
+	wg.Wait()
+	return sprites
+}
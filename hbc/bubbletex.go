@@ -0,0 +1,122 @@
+package hbc
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// BubbleConfig replaces the bubble textures with ones drawn at startup.
+type BubbleConfig struct {
+	// Procedural draws every bubble instead of using the PNGs. Missing
+	// bubble PNGs are drawn either way.
+	Procedural bool `json:"procedural,omitempty"`
+	// Sizes lists the bubble diameters to pick from, each equally likely, up
+	// to maxBubbleSize. Empty keeps the sizes of the original bubbles.
+	Sizes []float64 `json:"sizes,omitempty"`
+	// Style is "clear", a thin film with a bright rim, or "glossy", denser
+	// with a softer rim.
+	Style string `json:"style,omitempty"`
+	// Bursts and Emitters add scripted bubbles to the random ones. The
+	// edit-bubbles command places them with the mouse.
+	Bursts   []BurstConfig   `json:"bursts,omitempty"`
+	Emitters []EmitterConfig `json:"emitters,omitempty"`
+	// Layout is a bubble layout file saved by export-bubbles, played
+	// instead of generating bubbles from the seed.
+	Layout string `json:"layout,omitempty"`
+}
+
+// bubbleStyle sets how opaque each part of a drawn bubble is.
+type bubbleStyle struct {
+	fill      float64
+	rim       float64
+	highlight float64
+}
+
+var bubbleStyles = map[string]bubbleStyle{
+	"clear":  {fill: 0.06, rim: 0.9, highlight: 0.9},
+	"glossy": {fill: 0.22, rim: 0.6, highlight: 1},
+}
+
+// maxBubbleSize keeps a drawn bubble's texture within the largest image the
+// GPU takes at the highest render scale.
+const maxBubbleSize = 1024
+
+// setupProceduralBubbleTypes swaps in bubble types of the configured sizes.
+// Their textures are drawn in drawBubbleTextures once loading finishes.
+func (g *Intro) setupProceduralBubbleTypes() {
+	c := g.cfg.Bubbles
+	if _, ok := bubbleStyles[c.Style]; !ok && c.Style != "" {
+		log.Printf("Warning: Unknown bubble style %q, using clear\n", c.Style)
+	}
+	if !c.Procedural || len(c.Sizes) == 0 {
+		return
+	}
+	g.bubbleTypes = nil
+	for _, size := range c.Sizes {
+		if size <= 0 || size > maxBubbleSize {
+			log.Printf("Warning: Ignoring bubble size %g, want 0 to %d\n", size, maxBubbleSize)
+			continue
+		}
+		name := fmt.Sprintf("bubble-%g", size)
+		g.bubbleTypes = append(g.bubbleTypes, BubbleType{name: name, width: size, height: size, chance: 1})
+	}
+	if len(g.bubbleTypes) == 0 {
+		g.setupBubbleTypes()
+	}
+}
+
+// drawBubbleTextures draws the textures of bubble types that are procedural
+// or whose PNG is missing, at the render scale. missing holds the texture
+// names that failed to load.
+func (g *Intro) drawBubbleTextures(missing map[string]bool) {
+	for _, bt := range g.bubbleTypes {
+		if _, loaded := g.textures[bt.name]; loaded && !missing[bt.name] && !g.cfg.Bubbles.Procedural {
+			continue
+		}
+		g.drawBubbleTexture(bt)
+	}
+}
+
+func (g *Intro) drawBubbleTexture(bt BubbleType) *ebiten.Image {
+	style, ok := bubbleStyles[g.cfg.Bubbles.Style]
+	if !ok {
+		style = bubbleStyles["clear"]
+	}
+	px := max(int(math.Ceil(bt.width*g.textureScale())), 1)
+	img := ebiten.NewImageFromImage(bubbleTexture(px, style))
+	g.textures[bt.name] = img
+	g.textureDensity[img] = float64(px) / bt.width
+	return img
+}
+
+// bubbleTexture draws a white bubble of px pixels across: a faint film
+// that turns opaque towards the rim, as light does at grazing angles, and a
+// highlight to the upper left. Themes tint it like the PNG bubbles.
+func bubbleTexture(px int, s bubbleStyle) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, px, px))
+	c := float64(px) / 2
+	for y := range px {
+		for x := range px {
+			dx, dy := (float64(x)+0.5-c)/c, (float64(y)+0.5-c)/c
+			r := math.Hypot(dx, dy)
+			// Anti-alias the outline over one pixel.
+			edge := min(max((1-r)*c, 0), 1)
+			if edge == 0 {
+				continue
+			}
+
+			a := s.fill + s.rim*math.Pow(r, 6)
+			if h := 1 - math.Hypot(dx+0.4, dy+0.4)/0.3; h > 0 {
+				a = max(a, s.highlight*h*h)
+			}
+			v := uint8(min(a, 1) * edge * 255)
+			i := img.PixOffset(x, y)
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = v, v, v, v
+		}
+	}
+	return img
+}
@@ -0,0 +1,167 @@
+package hbc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	calibrateBeatFrames = 60
+	calibrateBeats      = 10
+	// calibrateWarmup beats of each round are not measured, while the user
+	// finds the rhythm.
+	calibrateWarmup = 2
+	calibrateWindow = 500 * time.Millisecond
+)
+
+// Calibration measures how late the sound arrives compared to the picture. The
+// user taps along with beeps they can only hear, then with flashes they can
+// only see; their reaction time is the same in both rounds, so the
+// difference between the two is the audio latency.
+type Calibration struct {
+	game  *Intro
+	beep  *audio.Player
+	round int
+	tick  int
+	beats []time.Time
+	taps  [2][]time.Duration
+	saved string
+}
+
+func NewCalibration(g *Intro) *Calibration {
+	c := &Calibration{game: g}
+	if g.audioContext != nil {
+		c.beep = g.audioContext.NewPlayerFromBytes(beepSamples())
+		c.beep.SetVolume(max(g.volume, 0.5))
+		g.setBufferSize(c.beep)
+	}
+	return c
+}
+
+// beepSamples is a short 880 Hz tone with a falling envelope, as 16-bit
+// stereo samples.
+func beepSamples() []byte {
+	n := sampleRate / 20
+	b := make([]byte, n*4)
+	for i := range n {
+		env := 1 - float64(i)/float64(n)
+		v := int16(math.Sin(2*math.Pi*880*float64(i)/sampleRate) * env * 0.5 * math.MaxInt16)
+		binary.LittleEndian.PutUint16(b[4*i:], uint16(v))
+		binary.LittleEndian.PutUint16(b[4*i+2:], uint16(v))
+	}
+	return b
+}
+
+func (c *Calibration) Update() error {
+	g := c.game
+	if g.input.JustPressed(ActionQuit) || g.quitRequested.Load() || ebiten.IsWindowBeingClosed() {
+		return ebiten.Termination
+	}
+	if c.round == 2 {
+		if g.input.JustPressed(ActionPause) {
+			c.round, c.taps, c.saved = 0, [2][]time.Duration{}, ""
+		}
+		return nil
+	}
+
+	c.tick++
+	if c.tick%calibrateBeatFrames == 0 && len(c.beats) < calibrateBeats {
+		c.beats = append(c.beats, time.Now())
+		if c.round == 0 && c.beep != nil {
+			c.beep.Rewind()
+			c.beep.Play()
+		}
+	}
+	if g.input.JustPressed(ActionPause) {
+		c.tap(time.Now())
+	}
+
+	if c.tick == (calibrateBeats+1)*calibrateBeatFrames {
+		c.round++
+		c.tick = 0
+		c.beats = nil
+		if c.round == 2 {
+			c.finish()
+		}
+	}
+	return nil
+}
+
+// tap records how far a key press was from the nearest measured beat.
+func (c *Calibration) tap(at time.Time) {
+	for i, beat := range c.beats {
+		d := at.Sub(beat)
+		if d > -calibrateWindow && d < calibrateWindow {
+			if i >= calibrateWarmup {
+				c.taps[c.round] = append(c.taps[c.round], d)
+			}
+			return
+		}
+	}
+}
+
+func mean(ds []time.Duration) time.Duration {
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+	return sum / time.Duration(len(ds))
+}
+
+func (c *Calibration) finish() {
+	if len(c.taps[0]) == 0 || len(c.taps[1]) == 0 {
+		c.saved = "Not enough taps to measure anything."
+		return
+	}
+
+	g := c.game
+	g.audioOffset = (mean(c.taps[0]) - mean(c.taps[1])).Round(time.Millisecond)
+	if err := g.savePreferences(); err != nil {
+		log.Printf("Warning: Could not save preferences: %v\n", err)
+		c.saved = fmt.Sprintf("Audio offset: %d ms (could not be saved)", g.audioOffset.Milliseconds())
+		return
+	}
+	c.saved = fmt.Sprintf("Audio offset: %d ms, saved.", g.audioOffset.Milliseconds())
+}
+
+func (c *Calibration) Draw(screen *ebiten.Image) {
+	screen.Fill(color.Black)
+
+	var text string
+	switch c.round {
+	case 0:
+		text = "Close your eyes and tap Space on every beep."
+	case 1:
+		text = "Tap Space on every flash."
+		if len(c.beats) > 0 && c.tick-len(c.beats)*calibrateBeatFrames < flashFadeFrames {
+			screen.Fill(color.White)
+		}
+	default:
+		text = c.saved + "\n\nPress Space to measure again or Esc to quit."
+	}
+	if c.round < 2 {
+		text += fmt.Sprintf("\n\nRound %d of 2, beat %d of %d", c.round+1, len(c.beats), calibrateBeats)
+	}
+	ebitenutil.DebugPrintAt(screen, text, 40, screenHeight/2-24)
+}
+
+func runCalibrate(args []string) error {
+	fset, common := newFlagSet("calibrate")
+	fset.Parse(args)
+
+	common.preferences = true
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+
+	return runScene(g, "go-hbc-intro audio calibration", func() Scene { return NewCalibration(g) })
+}
@@ -0,0 +1,43 @@
+package hbc
+
+import "time"
+
+// maxLagFrames is how far the animation may fall behind the wall clock, and
+// so behind the music, before it jumps ahead. Smaller delays are caught up
+// by ebiten running extra updates.
+const maxLagFrames = 6
+
+// catchUp skips the animation forward after a stall, such as the window
+// being dragged on Windows or the system swapping, so that it lines up with
+// the music again. The music is the reference; it keeps playing through a
+// stall on the audio thread.
+func (g *Intro) catchUp() {
+	// A video wall follower keeps to the leader's clock instead.
+	if g.offline || g.wall.following() {
+		return
+	}
+	now := time.Now()
+	if g.clockStart.IsZero() {
+		g.clockStart, g.clockTicks = now, 0
+	}
+	g.clockTicks++
+
+	behind := int(now.Sub(g.clockStart).Seconds()*60) - g.clockTicks
+	if behind <= maxLagFrames {
+		return
+	}
+	g.clockTicks += behind
+	g.count += behind
+	g.ticks += behind
+	for g.count >= loopEnd {
+		g.count -= loopEnd - loopStart
+		g.loopCount++
+		g.emit(EventLoopWrapped)
+	}
+}
+
+// resetClock starts measuring lag afresh, after the animation was paused or
+// moved on purpose.
+func (g *Intro) resetClock() {
+	g.clockStart = time.Time{}
+}
@@ -0,0 +1,452 @@
+package hbc
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"run", "play the intro (default)", runIntro},
+		{"gallery", "browse the loaded textures", runGallery},
+		{"edit-bubbles", "place bubble bursts and emitters with the mouse", runEditBubbles},
+		{"edit-timeline", "move the beats of the opening with the mouse", runEditTimeline},
+		{"export-bubbles", "save the generated bubbles to a layout file", runExportBubbles},
+		{"import-bubbles", "play the bubbles of a layout file instead of random ones", runImportBubbles},
+		{"record", "render the loop to an animated GIF or a PNG sequence", runRecord},
+		{"export-frame", "render a single frame to a PNG file", runExportFrame},
+		{"sprites", "render an element's animation to a sprite sheet with JSON metadata", runSprites},
+		{"bench", "measure rendering performance", runBench},
+		{"check-seam", "check that the loop wraps around without a visible jump", runCheckSeam},
+		{"calibrate", "measure the audio delay and save it as the audio offset", runCalibrate},
+		{"control", "playback controls for an intro started with run -control-window", runControl},
+		{"verify-assets", "check that every asset loads and decodes", runVerifyAssets},
+		{"list-assets", "print every asset with its size and users", listAssets},
+		{"version", "print version and build information", runVersion},
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [command] [flags]\n\nCommands:\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the flags of a command.\n", os.Args[0])
+}
+
+// Main runs the go-hbc-intro command line with args, not including the
+// program name. Without a subcommand, or when the first argument is a flag,
+// the intro is run. Windows screensaver arguments are handled first.
+func Main(args []string) error {
+	if mode, ok := screensaverArgs(args); ok {
+		return runScreensaver(mode)
+	}
+
+	name := "run"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		usage()
+		return nil
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(args)
+		}
+	}
+	usage()
+	return fmt.Errorf("unknown command %q", name)
+}
+
+// commonFlags are the flags shared by every command that loads the intro.
+type commonFlags struct {
+	config    string
+	assets    string
+	assetsURL string
+	assetsSum string
+	strict    bool
+	seed      int64
+	profile   string
+	renderer  string
+	variant   string
+	backdrop  string
+	layout    string
+
+	integerScale bool
+	audioBuffer  int
+	audioDevice  string
+
+	// preferences loads the saved preferences before the flags below
+	// override them.
+	preferences   bool
+	theme         string
+	volume        float64
+	fullscreen    bool
+	reducedMotion bool
+	audioOffset   int
+
+	// viewport is set by run's -viewport, which the layout needs before
+	// the intro is created.
+	viewport string
+	// noAudio is set for headless runs, which must not open a sound device.
+	noAudio bool
+
+	fset *flag.FlagSet
+}
+
+func (c *commonFlags) register(fset *flag.FlagSet) {
+	fset.StringVar(&c.config, "config", "", "path to a JSON config file")
+	fset.StringVar(&c.assets, "assets", "", "load assets from a zip archive or directory instead of the embedded ones")
+	fset.StringVar(&c.assetsURL, "assets-url", "", "download an asset pack zip from this URL on first run and cache it")
+	fset.StringVar(&c.assetsSum, "assets-sha256", "", "expected SHA-256 of the pack given by -assets-url")
+	fset.BoolVar(&c.strict, "strict", false, "refuse to start when an asset is missing or broken")
+	fset.Int64Var(&c.seed, "seed", 0, "random seed for the bubble layout (0 picks one)")
+	fset.StringVar(&c.profile, "profile", "", "render profile: default, pi or netbook")
+	fset.StringVar(&c.renderer, "renderer", "", "renderer: texture or vector")
+	fset.StringVar(&c.variant, "variant", "", "intro to play: hbc or bootmii")
+	fset.StringVar(&c.backdrop, "backdrop", "", "alternative sky behind the water, such as starfield")
+	fset.StringVar(&c.layout, "bubble-layout", "", "play the bubbles saved in this `file` by export-bubbles instead of random ones")
+	fset.BoolVar(&c.integerScale, "integer-scale", false, "scale only by whole factors, with black borders")
+	fset.IntVar(&c.audioBuffer, "audio-buffer", 0, "audio buffer size in `ms`, larger to fix crackling (0 keeps the default)")
+	fset.StringVar(&c.audioDevice, "audio-device", "", "play the sound on this output `device` instead of the default, where the platform allows")
+	fset.StringVar(&c.theme, "theme", "", "theme: day or night")
+	fset.Float64Var(&c.volume, "volume", 1, "music volume from 0 to 1")
+	fset.BoolVar(&c.fullscreen, "fullscreen", false, "start in fullscreen")
+	fset.BoolVar(&c.reducedMotion, "reduced-motion", false, "calm the animation and dim the flashes")
+	fset.IntVar(&c.audioOffset, "audio-offset", 0, "start the music this many `ms` early to make up for audio delay (negative delays it)")
+	c.fset = fset
+}
+
+// applyFlags applies the flags given on the command line on top of the config
+// and preferences.
+func (c *commonFlags) applyFlags(g *Intro) {
+	if c.fset == nil {
+		return
+	}
+	c.fset.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "theme":
+			g.setTheme(c.theme)
+		case "volume":
+			g.volume = min(max(c.volume, 0), 1)
+		case "fullscreen":
+			ebiten.SetFullscreen(c.fullscreen)
+		case "reduced-motion":
+			g.reducedMotion = c.reducedMotion
+		case "audio-offset":
+			g.audioOffset = time.Duration(c.audioOffset) * time.Millisecond
+		}
+	})
+}
+
+func (c *commonFlags) openAssets() (fs.FS, error) {
+	if c.assetsURL != "" && c.assets == "" {
+		path, err := cachedAssetPack(c.assetsURL, c.assetsSum)
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch asset pack %s: %w", c.assetsURL, err)
+		}
+		c.assets = path
+	}
+
+	assets, err := openAssets(c.assets)
+	if err != nil {
+		return nil, fmt.Errorf("could not open assets %s: %w", c.assets, err)
+	}
+	return assets, nil
+}
+
+// newIntro loads the config and assets named by the flags.
+func (c *commonFlags) newIntro() (*Intro, error) {
+	cfg, err := LoadConfig(c.config)
+	if err != nil {
+		log.Printf("Warning: Could not load config %s: %v\n", c.config, err)
+	}
+	if c.seed != 0 {
+		cfg.Seed = c.seed
+	}
+	if c.profile != "" {
+		cfg.Profile = c.profile
+	}
+	if c.renderer != "" {
+		cfg.Renderer = c.renderer
+	}
+	if c.variant != "" {
+		cfg.Variant = c.variant
+	}
+	if c.backdrop != "" {
+		cfg.Backdrop = c.backdrop
+	}
+	if c.layout != "" {
+		cfg.Bubbles.Layout = c.layout
+	}
+	if c.integerScale {
+		cfg.IntegerScale = true
+	}
+	if c.audioBuffer > 0 {
+		cfg.AudioBuffer = c.audioBuffer
+	}
+	if c.audioDevice != "" {
+		cfg.AudioDevice = c.audioDevice
+	}
+	if c.viewport != "" {
+		cfg.Wall.Viewport = c.viewport
+	}
+	if c.noAudio {
+		cfg.NoAudio = true
+	}
+
+	assets, err := c.openAssets()
+	if err != nil {
+		return nil, err
+	}
+
+	g := New(cfg, assets)
+	g.assetsSource = c.assets
+	g.strict = c.strict
+	if c.preferences {
+		g.loadPreferences()
+	}
+	c.applyFlags(g)
+	return g, nil
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *commonFlags) {
+	fset := flag.NewFlagSet(name, flag.ExitOnError)
+	common := &commonFlags{}
+	common.register(fset)
+	return fset, common
+}
+
+// runScene opens the window and runs next once the assets have loaded.
+func runScene(g *Intro, title string, next func() Scene) error {
+	w, h := g.viewportSize()
+	ebiten.SetWindowSize(int(w), int(h))
+	ebiten.SetWindowTitle(title)
+	g.setWindowIcon()
+	ebiten.SetTPS(60)
+	ebiten.SetWindowClosingHandled(true)
+	ebiten.SetScreenClearedEveryFrame(false)
+	if g.cfg.UncappedFPS {
+		// The same as FPSModeVsyncOffMaximum. Only drawing is uncapped;
+		// Update still runs at 60 TPS.
+		ebiten.SetVsyncEnabled(false)
+	}
+
+	app := &App{game: g}
+	if g.kiosk {
+		app.kiosk = newKioskWatchdog(app)
+	}
+	app.SetScene(newLoadingScene(app, g, next))
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		g.quitRequested.Store(true)
+	}()
+
+	defer app.recoverCrash()
+	return ebiten.RunGame(app)
+}
+
+// Run opens a window, loads the assets and plays the intro until the window
+// is closed.
+func Run(in *Intro) error {
+	return runScene(in, in.windowTitle(), func() Scene { return in })
+}
+
+func runIntro(args []string) error {
+	fset, common := newFlagSet("run")
+	confirmQuit := fset.Bool("confirm-quit", false, "require pressing Esc twice to quit")
+	resume := fset.Bool("resume", false, "continue from where the last run was quit")
+	controlWindow := fset.Bool("control-window", false, "open a separate window with playback controls")
+	skipIntro := fset.Bool("skip-intro", false, "start at the loop, after the opening and the flash")
+	wiiload := fset.Bool("wiiload", false, "show transfers sent with wiiload to port 4299; nothing is run")
+	headless := fset.Bool("headless", false, "run -frames ticks without a window or audio and exit, for smoke tests")
+	frames := fset.Int("frames", 2*loopEnd, "ticks to run with -headless")
+	draw := fset.Bool("draw", false, "with -headless, also draw every tick offscreen")
+	timecode := fset.Bool("timecode", false, "burn the frame number and timecode into a corner, for lining up captures")
+	uncapped := fset.Bool("uncapped", false, "draw with vsync off as fast as possible, for benchmarks and latency tests")
+	windowTitle := fset.String("window-title", "", "title of the window (defaults to go-hbc-intro)")
+	windowIcon := fset.String("window-icon", "", "window icon: a texture in the asset pack or an image `file`")
+	kiosk := fset.Bool("kiosk", false, "run unattended: fullscreen, no cursor or keys, restart on errors; hold Esc to quit")
+	kioskLog := fset.String("kiosk-log", "", "with -kiosk, append the uptime and loop count to this `file` every hour")
+	maxFPS := fset.Int("max-fps", 0, "draw at most this many frames a second, for e-ink or remote desktops; the animation keeps its speed")
+	appsDir := fset.String("apps-dir", "", "show the apps in this SD card style apps `directory` in the app grid")
+	wall := fset.String("wall", "", "play frame-locked on a video wall as its `role`: leader or follower")
+	wallAddr := fset.String("wall-addr", "", "UDP `address` the video wall leader sends its clock to (defaults to broadcast on port 4300)")
+	viewport := fset.String("viewport", "", "draw only this part of the canvas, as `x,y,w,h` fractions such as 1/3,0,1/3,1")
+	// Flags given on the command line win over the page URL.
+	fset.Parse(append(queryFlags(fset), args...))
+
+	var state playbackState
+	if *resume {
+		var err error
+		state, err = loadState()
+		if err != nil {
+			log.Printf("Warning: Could not load playback state: %v\n", err)
+			*resume = false
+		} else if common.seed == 0 {
+			common.seed = state.Seed
+		}
+	}
+
+	// Headless runs are for CI, so they ignore what was saved locally.
+	common.preferences = !*headless
+	common.viewport = *viewport
+	common.noAudio = *headless
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	if *resume {
+		g.restoreState(state)
+		common.applyFlags(g)
+	} else if *skipIntro {
+		// Set up like a resumed run, so the music catches up once it loads.
+		g.count = loopStart
+		g.resumed = true
+	}
+	if *appsDir != "" {
+		g.cfg.Apps.Dir = *appsDir
+	}
+	if *timecode {
+		g.cfg.Timecode.Enabled = true
+	}
+	if *uncapped {
+		g.cfg.UncappedFPS = true
+	}
+	if *maxFPS > 0 {
+		g.SetFrameLimit(*maxFPS)
+	}
+	if *windowTitle != "" {
+		g.cfg.WindowTitle = *windowTitle
+	}
+	if *windowIcon != "" {
+		g.cfg.WindowIcon = *windowIcon
+	}
+	if *kiosk {
+		g.cfg.Kiosk.Enabled = true
+	}
+	if *kioskLog != "" {
+		g.cfg.Kiosk.Log = *kioskLog
+	}
+	if *wall != "" {
+		g.cfg.Wall.Role = *wall
+	}
+	if *wallAddr != "" {
+		g.cfg.Wall.Addr = *wallAddr
+	}
+	if *headless {
+		return runHeadless(g, *frames, *draw)
+	}
+	g.confirmQuit = *confirmQuit
+	g.persistState = true
+	if *controlWindow {
+		if err := g.startControlWindow(); err != nil {
+			log.Printf("Warning: Could not open the control window: %v\n", err)
+		}
+	}
+	g.startDiscordPresence(g.cfg.Discord)
+	g.startTwitchChat(g.cfg.Twitch)
+	g.startWeather(g.cfg.Weather)
+	g.startMIDI(g.cfg.MIDI)
+	g.startMic(g.cfg.Mic)
+	g.startTicker(g.cfg.Ticker)
+	g.startWiiload(g.cfg.Wiiload || *wiiload)
+	g.startTaskbarProgress()
+	g.startWall(g.cfg.Wall)
+	g.startJSAPI()
+
+	g.kiosk = g.cfg.Kiosk.Enabled
+	return runScene(g, g.windowTitle(), func() Scene { return g })
+}
+
+func runGallery(args []string) error {
+	fset, common := newFlagSet("gallery")
+	fset.Parse(args)
+
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+
+	return runScene(g, "go-hbc-intro asset gallery", func() Scene { return NewGallery(g) })
+}
+
+func runEditBubbles(args []string) error {
+	fset, common := newFlagSet("edit-bubbles")
+	out := fset.String("o", "", "config file to save the bursts and emitters to (defaults to -config, or config.json)")
+	fset.Parse(args)
+
+	path := *out
+	if path == "" {
+		path = common.config
+	}
+	if path == "" {
+		path = "config.json"
+	}
+
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	return runScene(g, "go-hbc-intro bubble editor", func() Scene { return newBubbleEditor(g, path) })
+}
+
+func runEditTimeline(args []string) error {
+	fset, common := newFlagSet("edit-timeline")
+	out := fset.String("o", "", "config file to save the timeline to (defaults to -config, or config.json)")
+	fset.Parse(args)
+
+	path := *out
+	if path == "" {
+		path = common.config
+	}
+	if path == "" {
+		path = "config.json"
+	}
+
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	return runScene(g, "go-hbc-intro timeline editor", func() Scene { return newTimelineEditor(g, path) })
+}
+
+func runVerifyAssets(args []string) error {
+	fset, common := newFlagSet("verify-assets")
+	fset.Parse(args)
+
+	assets, err := common.openAssets()
+	if err != nil {
+		return err
+	}
+
+	l := startAssetLoader(assets, texturePaths, 1)
+	l.wait()
+	if len(l.failures) > 0 {
+		return fmt.Errorf("%d of %d assets failed to load", len(l.failures), l.total)
+	}
+	fmt.Printf("All %d assets loaded.\n", l.total)
+	return nil
+}
@@ -0,0 +1,10 @@
+package hbc
+
+import (
+	"errors"
+	"image"
+)
+
+func copyImage(img *image.RGBA) error {
+	return errors.New("copying to the clipboard is not available in the browser")
+}
@@ -0,0 +1,45 @@
+//go:build !windows && !js
+
+package hbc
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// copyImage hands img to the desktop's clipboard tool as a PNG: wl-copy on
+// Wayland, xclip on X11 and osascript on macOS.
+func copyImage(img *image.RGBA) error {
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "darwin" {
+		f, err := os.CreateTemp("", "hbc-*.png")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(f.Name())
+		if _, err := f.Write(data.Bytes()); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		script := `set the clipboard to (read (POSIX file "` + f.Name() + `") as «class PNGf»)`
+		return exec.Command("osascript", "-e", script).Run()
+	}
+
+	cmd := exec.Command("xclip", "-selection", "clipboard", "-t", "image/png", "-i")
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		cmd = exec.Command("wl-copy", "--type", "image/png")
+	}
+	cmd.Stdin = &data
+	return cmd.Run()
+}
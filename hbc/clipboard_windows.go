@@ -0,0 +1,99 @@
+package hbc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32   = syscall.NewLazyDLL("user32.dll")
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	openClipboard            = user32.NewProc("OpenClipboard")
+	closeClipboard           = user32.NewProc("CloseClipboard")
+	emptyClipboard           = user32.NewProc("EmptyClipboard")
+	setClipboardData         = user32.NewProc("SetClipboardData")
+	registerClipboardFormatW = user32.NewProc("RegisterClipboardFormatW")
+	globalAlloc              = kernel32.NewProc("GlobalAlloc")
+	globalFree               = kernel32.NewProc("GlobalFree")
+	globalLock               = kernel32.NewProc("GlobalLock")
+	globalUnlock             = kernel32.NewProc("GlobalUnlock")
+	moveMemory               = kernel32.NewProc("RtlMoveMemory")
+)
+
+// copyImage puts img on the clipboard both as PNG, which keeps alpha and is
+// what browsers and chat apps paste, and as a plain bitmap for older
+// programs such as Paint.
+func copyImage(img *image.RGBA) error {
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, img); err != nil {
+		return err
+	}
+
+	// The clipboard belongs to the thread that opened it, so every call up
+	// to CloseClipboard has to be made from that thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if r, _, err := openClipboard.Call(0); r == 0 {
+		return err
+	}
+	defer closeClipboard.Call()
+	emptyClipboard.Call()
+
+	const cfDIB = 8
+	if err := setClipboard(cfDIB, dib(img)); err != nil {
+		return err
+	}
+	name, _ := syscall.UTF16PtrFromString("PNG")
+	if format, _, _ := registerClipboardFormatW.Call(uintptr(unsafe.Pointer(name))); format != 0 {
+		return setClipboard(format, pngData.Bytes())
+	}
+	return nil
+}
+
+func setClipboard(format uintptr, data []byte) error {
+	const gmemMoveable = 0x2
+	h, _, err := globalAlloc.Call(gmemMoveable, uintptr(len(data)))
+	if h == 0 {
+		return err
+	}
+	p, _, err := globalLock.Call(h)
+	if p == 0 {
+		globalFree.Call(h)
+		return err
+	}
+	moveMemory.Call(p, uintptr(unsafe.Pointer(&data[0])), uintptr(len(data)))
+	globalUnlock.Call(h)
+
+	// The clipboard owns the memory once this succeeds.
+	if r, _, err := setClipboardData.Call(format, h); r == 0 {
+		globalFree.Call(h)
+		return err
+	}
+	return nil
+}
+
+// dib encodes img as a 32-bit bottom-up device independent bitmap.
+func dib(img *image.RGBA) []byte {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	b := make([]byte, 40, 40+w*h*4)
+	binary.LittleEndian.PutUint32(b[0:], 40)
+	binary.LittleEndian.PutUint32(b[4:], uint32(w))
+	binary.LittleEndian.PutUint32(b[8:], uint32(h))
+	binary.LittleEndian.PutUint16(b[12:], 1)
+	binary.LittleEndian.PutUint16(b[14:], 32)
+	binary.LittleEndian.PutUint32(b[20:], uint32(w*h*4))
+
+	for y := h - 1; y >= 0; y-- {
+		row := img.Pix[y*img.Stride : y*img.Stride+w*4]
+		for x := 0; x < len(row); x += 4 {
+			b = append(b, row[x+2], row[x+1], row[x], row[x+3])
+		}
+	}
+	return b
+}
@@ -0,0 +1,103 @@
+package hbc
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	clockTimeSize = 28
+	clockDateSize = 14
+	clockMargin   = 20
+)
+
+// clockColor is the soft grey of the Wii menu clock.
+var clockColor = color.RGBA{0x8c, 0x8c, 0x8c, 0xff}
+
+type ClockConfig struct {
+	Enabled bool `json:"enabled"`
+	// Corner is one of "top-left", "top-right", "bottom-left" or
+	// "bottom-right" (the default).
+	Corner string `json:"corner,omitempty"`
+	// Hour24 shows 15:04 instead of 3:04 PM.
+	Hour24 bool `json:"hour24,omitempty"`
+}
+
+// clock caches the rendered strings so they only change once a minute.
+type clock struct {
+	cfg    ClockConfig
+	minute time.Time
+	time   string
+	date   string
+}
+
+func (c *clock) update(now time.Time) {
+	minute := now.Truncate(time.Minute)
+	if minute.Equal(c.minute) {
+		return
+	}
+	c.minute = minute
+
+	if c.cfg.Hour24 {
+		c.time = now.Format("15:04")
+	} else {
+		c.time = now.Format("3:04 PM")
+	}
+	c.date = now.Format("Mon 1/2")
+}
+
+func (g *Intro) drawClock(ui *ebiten.Image) {
+	if !g.cfg.Clock.Enabled {
+		return
+	}
+	g.clock.cfg = g.cfg.Clock
+	g.clock.update(time.Now())
+
+	timeFace := uiFace(clockTimeSize)
+	dateFace := uiFace(clockDateSize)
+	tw, th := text.Measure(g.clock.time, timeFace, 0)
+	dw, dh := text.Measure(g.clock.date, dateFace, 0)
+	w := max(tw, dw)
+	h := th + dh
+
+	corner := g.cfg.Clock.Corner
+	if corner == "" {
+		corner = "bottom-right"
+	}
+	x, y := g.cornerPosition(corner, w, h, clockMargin)
+	if g.ticker != nil && y+h > g.height-tickerHeight {
+		y -= tickerHeight
+	}
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x+(w-tw)/2, y)
+	op.ColorScale.ScaleWithColor(clockColor)
+	text.Draw(ui, g.clock.time, timeFace, op)
+
+	op = &text.DrawOptions{}
+	op.GeoM.Translate(x+(w-dw)/2, y+th)
+	op.ColorScale.ScaleWithColor(clockColor)
+	text.Draw(ui, g.clock.date, dateFace, op)
+}
+
+// cornerPosition places a w by h box in the named corner, or the center. The
+// layout matches the configured aspect ratio and ebiten letterboxes it into
+// the window, so logical corners stay on screen at any window size.
+func (g *Intro) cornerPosition(corner string, w, h, margin float64) (float64, float64) {
+	left, top := margin, margin
+	right, bottom := g.width-margin-w, g.height-margin-h
+	switch corner {
+	case "top-left":
+		return left, top
+	case "top-right":
+		return right, top
+	case "bottom-left":
+		return left, bottom
+	case "center":
+		return (g.width - w) / 2, (g.height - h) / 2
+	}
+	return right, bottom
+}
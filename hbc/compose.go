@@ -0,0 +1,58 @@
+package hbc
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// DrawTo draws the intro, overlays included, stretched over the bounds of
+// target. It lets another Ebitengine game composite the intro into part of
+// its own screen, such as a sub-image for a picture-in-picture panel or the
+// texture of a TV in its scene; the game calls Update itself. The frame is
+// rendered at the render resolution first, so keep target's aspect ratio
+// close to the intro's layout to avoid stretching it.
+//
+// An intro not started with Run loads its assets in the background from the
+// first call on, and draws nothing until they are ready.
+func (g *Intro) DrawTo(target *ebiten.Image) {
+	if !g.loadEmbedded() {
+		return
+	}
+	g.frameTimes.tick(time.Now())
+
+	w, h := g.renderSize()
+	if g.composeCanvas == nil || g.composeCanvas.Bounds().Dx() != w || g.composeCanvas.Bounds().Dy() != h {
+		if g.composeCanvas != nil {
+			g.composeCanvas.Deallocate()
+		}
+		g.composeCanvas = ebiten.NewImage(w, h)
+	}
+	g.composeCanvas.Clear()
+	g.drawLayers(g.composeCanvas)
+
+	b := target.Bounds()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(b.Dx())/float64(w), float64(b.Dy())/float64(h))
+	op.GeoM.Translate(float64(b.Min.X), float64(b.Min.Y))
+	op.Filter = ebiten.FilterLinear
+	target.DrawImage(g.composeCanvas, op)
+}
+
+// loadEmbedded does the loading scene's work for an intro drawn with DrawTo,
+// reporting whether the assets are ready.
+func (g *Intro) loadEmbedded() bool {
+	if g.loaded {
+		return true
+	}
+	if g.embedLoader == nil {
+		g.setupTextureCache()
+		g.embedLoader = startAssetLoader(g.assets, g.eagerTextures(), g.renderScale)
+	}
+	if !g.embedLoader.finished() {
+		return false
+	}
+	g.finishLoading(g.embedLoader)
+	g.embedLoader = nil
+	return true
+}
@@ -0,0 +1,183 @@
+package hbc
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Config struct {
+	Keys map[Action]ebiten.Key `json:"keys"`
+
+	// Seed drives the bubble layout. Zero picks a new one on every run.
+	Seed int64 `json:"seed,omitempty"`
+
+	// Profile names a preset from profiles, e.g. "pi".
+	Profile string `json:"profile,omitempty"`
+	// Theme is the name of the starting theme, "day" or "night".
+	Theme string `json:"theme,omitempty"`
+	// TitleMotion maps theme names to how the title moves in that theme.
+	// Themes left out keep the original bob.
+	TitleMotion map[string]TitleMotionConfig `json:"title_motion,omitempty"`
+	// Aspect composes the scene for another aspect ratio: wider ones such as
+	// "21:9" extend the water to the sides, portrait ones such as "9:16"
+	// stack it vertically. Empty keeps the original 810x456.
+	Aspect string `json:"aspect,omitempty"`
+	// IntegerScale only enlarges the picture by whole factors, leaving black
+	// borders instead of blurring pixels.
+	IntegerScale bool `json:"integer_scale,omitempty"`
+	// ReducedMotion calms the swaying of the waves and title and dims the
+	// flashes.
+	ReducedMotion bool `json:"reduced_motion,omitempty"`
+	// LoopCrossfade blends this many frames across the jump from the end of
+	// the loop back to its start. Zero turns it off.
+	LoopCrossfade int `json:"loop_crossfade,omitempty"`
+	// Blend maps animation layers ("background", "waves", "bubbles", "title",
+	// "flash") to a blend mode: "normal", "add", "multiply" or "screen".
+	Blend map[string]string `json:"blend,omitempty"`
+	// AudioBuffer sets how many milliseconds of audio each player buffers
+	// ahead. Larger values fix crackling on slow audio stacks at the cost of
+	// latency; zero keeps the default.
+	AudioBuffer int `json:"audio_buffer_ms,omitempty"`
+	// AudioDevice plays the intro's sound on another output than the
+	// system default: a PulseAudio or PipeWire sink name on Linux, or a
+	// device ID or label in the browser. Windows and macOS always use the
+	// default.
+	AudioDevice string `json:"audio_device,omitempty"`
+	// NoAudio creates no audio context, so the intro plays silently
+	// without needing a sound device, as headless runs and tests do.
+	NoAudio bool `json:"no_audio,omitempty"`
+	// SFX plays a sound for bubbles released by a burst, chat or the
+	// microphone, and a pop when they reach the top, panned by where they
+	// are on screen.
+	SFX bool `json:"sfx,omitempty"`
+	// Ambience plays a quiet underwater rumble under the opening, fading out
+	// as the waves settle.
+	Ambience bool `json:"ambience,omitempty"`
+	// Renderer is "texture" (the default) or "vector", which draws the
+	// waves, fade and bubbles as paths that stay sharp at any size.
+	Renderer string `json:"renderer,omitempty"`
+	// Variant picks the intro: "hbc" (the default), the Homebrew Channel
+	// banner, or "bootmii", a console-style boot screen.
+	Variant string `json:"variant,omitempty"`
+	// Backdrop adds an alternative sky behind the water, such as
+	// "starfield", which shows stars above the waterline at night.
+	Backdrop string `json:"backdrop,omitempty"`
+	// PointerCursor replaces the system cursor with a Wii remote's hand.
+	PointerCursor bool `json:"pointer_cursor,omitempty"`
+	// Wiiload listens on the Homebrew Channel's port and shows its transfer
+	// dialog when wiiload sends something. Nothing received is run.
+	Wiiload bool `json:"wiiload,omitempty"`
+	// LazyTextures decodes each texture when it is first drawn instead of
+	// while loading, and frees the ones the scene stops using.
+	LazyTextures bool `json:"lazy_textures,omitempty"`
+	// UncappedFPS turns vsync off and draws as often as the GPU allows, for
+	// benchmarks and latency tests. The animation still advances 60 times a
+	// second.
+	UncappedFPS bool `json:"uncapped_fps,omitempty"`
+	// FrameLimit draws the window at most this many times a second, for
+	// e-ink panels and remote desktops, without slowing the animation.
+	// Zero draws every frame.
+	FrameLimit int `json:"frame_limit,omitempty"`
+	// WindowTitle replaces "go-hbc-intro" as the title of the intro's
+	// window.
+	WindowTitle string `json:"window_title,omitempty"`
+	// WindowIcon is the window's icon: the name of a texture in the asset
+	// pack, or the path of an image file. It defaults to a bubble.
+	WindowIcon string `json:"window_icon,omitempty"`
+
+	Bubbles BubbleConfig  `json:"bubbles"`
+	Credits CreditsConfig `json:"credits"`
+	Apps    AppsConfig    `json:"apps"`
+	SDIcon  SDIconConfig  `json:"sd_icon"`
+	// Adaptive drops bubbles while the frame rate is too low.
+	Adaptive  AdaptiveConfig  `json:"adaptive_bubbles"`
+	Clock     ClockConfig     `json:"clock"`
+	Watermark WatermarkConfig `json:"watermark"`
+	Ticker    TickerConfig    `json:"ticker"`
+	Timecode  TimecodeConfig  `json:"timecode"`
+	Timeline  TimelineConfig  `json:"timeline"`
+	Kiosk     KioskConfig     `json:"kiosk"`
+	Wall      WallConfig      `json:"video_wall"`
+
+	Discord DiscordConfig `json:"discord"`
+	Twitch  TwitchConfig  `json:"twitch"`
+	Weather WeatherConfig `json:"weather"`
+	MIDI    MIDIConfig    `json:"midi"`
+	Mic     MicConfig     `json:"mic"`
+}
+
+// DefaultConfig is the configuration used for anything a config file leaves
+// out.
+func DefaultConfig() Config {
+	return Config{
+		Keys: defaultKeyBindings(),
+	}
+}
+
+// userConfigPath is where the config lives when none is given on the command
+// line, e.g. %AppData%\go-hbc-intro\config.json on Windows.
+func userConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-hbc-intro", "config.json"), nil
+}
+
+// LoadConfig reads a JSON config file on top of the defaults, so a file only
+// needs to list the settings it changes.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// updateConfigFile lets edit change the top-level settings of the config
+// file at path and writes it back. Settings edit leaves alone are kept as
+// they were, and a missing file is created.
+func updateConfigFile(path string, edit func(doc map[string]json.RawMessage) error) error {
+	doc := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	if err := edit(doc); err != nil {
+		return err
+	}
+	data, err = json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func setConfigKey(doc map[string]json.RawMessage, key string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	doc[key] = raw
+	return nil
+}
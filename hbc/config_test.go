@@ -0,0 +1,38 @@
+package hbc
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func FuzzLoadConfig(f *testing.F) {
+	def, err := json.Marshal(DefaultConfig())
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(def)
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`{"seed": 42, "aspect": "21:9", "theme": "night", "profile": "pi"}`))
+	f.Add([]byte(`{"bubbles": {"procedural": true, "sizes": [32, 64]}, "blend": {"bubbles": "add"}}`))
+	f.Add([]byte(`{"aspect": "9:16", "loop_crossfade": -1, "audio_buffer_ms": 1e9}`))
+	f.Add([]byte(`{"video_wall": {"role": "follower", "viewport": "1/3,0,1/3,1"}, "aspect": "48:9"}`))
+	f.Add([]byte(`{"timeline": {"title": -5}}`))
+	f.Add([]byte(`{"seed": "not a number"}`))
+
+	path := filepath.Join(f.TempDir(), "config.json")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			return
+		}
+		// Whatever loads has to survive the parsing done at startup.
+		parseAspect(cfg.Aspect)
+		parseViewport(cfg.Wall.Viewport)
+		cfg.Timeline.resolve()
+	})
+}
@@ -0,0 +1,155 @@
+package hbc
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The control window is a second go-hbc-intro process running the control
+// subcommand, since ebiten drives a single window per process. The two talk
+// over a local TCP connection, one command per line:
+//
+//	control window -> intro:  pause | play | seek <frame> | volume <0-1> |
+//	                          frame-limit <fps, 0 for none>
+//	intro -> control window:  state <frame> <paused 0|1> <volume>
+
+type controlCommand struct {
+	op    string
+	value float64
+}
+
+func parseControlCommand(line string) (controlCommand, error) {
+	op, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	c := controlCommand{op: op}
+	switch op {
+	case "pause", "play":
+		return c, nil
+	case "seek", "volume", "frame-limit":
+		v, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return c, err
+		}
+		c.value = v
+		return c, nil
+	}
+	return c, fmt.Errorf("unknown command %q", op)
+}
+
+// startControlWindow listens on a loopback port and launches the control
+// window pointed at it.
+func (g *Intro) startControlWindow() error {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		ln.Close()
+		return err
+	}
+
+	g.controls = make(chan controlCommand, 16)
+	g.controlState = make(chan string, 1)
+	go g.serveControl(ln)
+
+	cmd := exec.Command(exe, "control", "-addr", ln.Addr().String())
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Start(); err != nil {
+		ln.Close()
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+func (g *Intro) serveControl(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			log.Printf("Warning: Control window listener stopped: %v\n", err)
+			return
+		}
+		go g.writeControlState(conn)
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			c, err := parseControlCommand(scanner.Text())
+			if err != nil {
+				log.Printf("Warning: Bad control command: %v\n", err)
+				continue
+			}
+			g.controls <- c
+		}
+		conn.Close()
+	}
+}
+
+func (g *Intro) writeControlState(conn net.Conn) {
+	for state := range g.controlState {
+		conn.SetWriteDeadline(time.Now().Add(time.Second))
+		if _, err := fmt.Fprintln(conn, state); err != nil {
+			return
+		}
+	}
+}
+
+// controlStateInterval is how often the control window hears about the
+// playback position, in ticks.
+const controlStateInterval = 6
+
+func (g *Intro) updateControls() {
+	if g.controls == nil {
+		return
+	}
+	for len(g.controls) > 0 {
+		c := <-g.controls
+		switch c.op {
+		case "pause":
+			g.setPaused(true)
+		case "play":
+			g.setPaused(false)
+		case "seek":
+			g.seek(int(c.value))
+		case "volume":
+			g.volume = min(max(c.value, 0), 1)
+			g.setVolume(g.baseVolume())
+		case "frame-limit":
+			g.SetFrameLimit(int(c.value))
+		}
+	}
+
+	// The browser API reads the state itself.
+	if g.controlState == nil {
+		return
+	}
+	g.controlTick++
+	if g.controlTick%controlStateInterval != 0 {
+		return
+	}
+	paused := 0
+	if g.paused {
+		paused = 1
+	}
+	state := fmt.Sprintf("state %d %d %.2f", g.count, paused, g.volume)
+	select {
+	case <-g.controlState:
+	default:
+	}
+	g.controlState <- state
+}
+
+// seek jumps to frame within the current pass through the animation and
+// moves the music along with it.
+func (g *Intro) seek(frame int) {
+	if frame < 0 || frame >= loopEnd {
+		return
+	}
+	g.count = frame
+	g.seekAudio()
+}
@@ -0,0 +1,195 @@
+package hbc
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"net"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	panelWidth    = 420
+	panelHeight   = 120
+	panelPadding  = 16
+	panelBarH     = 8
+	panelFontSize = 14
+)
+
+var (
+	panelBackground = color.RGBA{40, 40, 40, 255}
+	panelTrack      = color.RGBA{80, 80, 80, 255}
+	panelAccent     = color.RGBA{52, 190, 237, 255}
+)
+
+// panelBar is a horizontal slider. Its value runs from 0 to 1.
+type panelBar struct {
+	x, y, w float32
+}
+
+func (b panelBar) hit(x, y int) bool {
+	return float32(x) >= b.x && float32(x) <= b.x+b.w && float32(y) >= b.y-6 && float32(y) <= b.y+panelBarH+6
+}
+
+func (b panelBar) value(x int) float64 {
+	return float64(min(max((float32(x)-b.x)/b.w, 0), 1))
+}
+
+func (b panelBar) draw(screen *ebiten.Image, v float64) {
+	vector.DrawFilledRect(screen, b.x, b.y, b.w, panelBarH, panelTrack, false)
+	vector.DrawFilledRect(screen, b.x, b.y, b.w*float32(v), panelBarH, panelAccent, false)
+}
+
+// controlPanel is the control window's ebiten.Game. It mirrors the state the
+// intro reports and sends commands back when clicked.
+type controlPanel struct {
+	conn net.Conn
+
+	mu     sync.Mutex
+	frame  int
+	paused bool
+	volume float64
+	closed bool
+
+	scrub, vol panelBar
+	dragging   *panelBar
+}
+
+func newControlPanel(conn net.Conn) *controlPanel {
+	p := &controlPanel{
+		conn:   conn,
+		volume: 1,
+		scrub:  panelBar{x: panelPadding + 90, y: panelPadding + 10, w: panelWidth - panelPadding*2 - 90},
+		vol:    panelBar{x: panelPadding + 90, y: panelPadding + 60, w: panelWidth - panelPadding*2 - 90},
+	}
+	go p.readState()
+	return p
+}
+
+func (p *controlPanel) readState() {
+	scanner := bufio.NewScanner(p.conn)
+	for scanner.Scan() {
+		var frame, paused int
+		var volume float64
+		if _, err := fmt.Sscanf(scanner.Text(), "state %d %d %f", &frame, &paused, &volume); err != nil {
+			continue
+		}
+		p.mu.Lock()
+		p.frame, p.paused, p.volume = frame, paused == 1, volume
+		p.mu.Unlock()
+	}
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+}
+
+func (p *controlPanel) send(format string, args ...any) {
+	fmt.Fprintf(p.conn, format+"\n", args...)
+}
+
+func (p *controlPanel) playButton() (x, y, w, h float32) {
+	return panelPadding, panelPadding, 72, 28
+}
+
+func (p *controlPanel) Update() error {
+	p.mu.Lock()
+	closed, paused := p.closed, p.paused
+	p.mu.Unlock()
+	if closed || ebiten.IsWindowBeingClosed() {
+		return ebiten.Termination
+	}
+
+	x, y := ebiten.CursorPosition()
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		bx, by, bw, bh := p.playButton()
+		switch {
+		case float32(x) >= bx && float32(x) <= bx+bw && float32(y) >= by && float32(y) <= by+bh:
+			if paused {
+				p.send("play")
+			} else {
+				p.send("pause")
+			}
+		case p.scrub.hit(x, y):
+			p.dragging = &p.scrub
+		case p.vol.hit(x, y):
+			p.dragging = &p.vol
+		}
+	}
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		p.dragging = nil
+	}
+
+	switch p.dragging {
+	case &p.scrub:
+		frame := int(p.scrub.value(x) * (loopEnd - 1))
+		p.mu.Lock()
+		moved := frame != p.frame
+		p.frame = frame
+		p.mu.Unlock()
+		if moved {
+			p.send("seek %d", frame)
+		}
+	case &p.vol:
+		v := p.vol.value(x)
+		p.send("volume %.2f", v)
+		p.mu.Lock()
+		p.volume = v
+		p.mu.Unlock()
+	}
+	return nil
+}
+
+func (p *controlPanel) Draw(screen *ebiten.Image) {
+	p.mu.Lock()
+	frame, paused, volume := p.frame, p.paused, p.volume
+	p.mu.Unlock()
+
+	screen.Fill(panelBackground)
+	face := uiFace(panelFontSize)
+	label := func(s string, x, y float64) {
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(x, y)
+		text.Draw(screen, s, face, op)
+	}
+
+	bx, by, bw, bh := p.playButton()
+	vector.DrawFilledRect(screen, bx, by, bw, bh, panelTrack, false)
+	if paused {
+		label("Play", float64(bx)+20, float64(by)+6)
+	} else {
+		label("Pause", float64(bx)+16, float64(by)+6)
+	}
+
+	p.scrub.draw(screen, float64(frame)/(loopEnd-1))
+	label(fmt.Sprintf("Frame %d/%d", frame, loopEnd), float64(p.scrub.x), float64(p.scrub.y)+14)
+
+	label("Volume", panelPadding, float64(p.vol.y)-6)
+	p.vol.draw(screen, volume)
+}
+
+func (p *controlPanel) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return panelWidth, panelHeight
+}
+
+// runControl implements the control subcommand, which run -control-window
+// starts by itself.
+func runControl(args []string) error {
+	fset, _ := newFlagSet("control")
+	addr := fset.String("addr", "", "address of the intro to control")
+	fset.Parse(args)
+
+	conn, err := net.Dial("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("could not connect to the intro: %w", err)
+	}
+	defer conn.Close()
+
+	ebiten.SetWindowSize(panelWidth, panelHeight)
+	ebiten.SetWindowTitle("go-hbc-intro controls")
+	return ebiten.RunGame(newControlPanel(conn))
+}
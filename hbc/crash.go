@@ -0,0 +1,72 @@
+package hbc
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// recoverCrash writes a crash log for a panic and then lets the panic
+// continue. It must be deferred directly. The game loop may run on its own
+// goroutine, so it is deferred both in Update/Draw and around RunGame; only
+// the first one to see the panic logs it.
+func (a *App) recoverCrash() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if a.crashed.Swap(true) {
+		panic(r)
+	}
+
+	path, err := writeCrashLog(a.game, r, debug.Stack())
+	if err != nil {
+		log.Printf("Could not write crash log: %v\n", err)
+	} else {
+		log.Printf("Crash log written to %s\n", path)
+	}
+	panic(r)
+}
+
+func crashLogDir() string {
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "go-hbc-intro", "crashes")
+	}
+	return filepath.Join(os.TempDir(), "go-hbc-intro-crashes")
+}
+
+func writeCrashLog(g *Intro, r any, stack []byte) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s crashed at %s\n", versionString(), time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "%s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Command line: %q\n\n", os.Args)
+	fmt.Fprintf(&b, "panic: %v\n\n", r)
+
+	if g != nil {
+		fmt.Fprintf(&b, "Frame: %d\n", g.count)
+		fmt.Fprintf(&b, "Seed: %d\n", g.seed)
+		cfg, err := json.MarshalIndent(g.cfg, "", "  ")
+		if err != nil {
+			fmt.Fprintf(&b, "Config: %v\n\n", err)
+		} else {
+			fmt.Fprintf(&b, "Config: %s\n\n", cfg)
+		}
+	}
+	b.Write(stack)
+
+	dir := crashLogDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "crash-"+time.Now().Format("20060102-150405")+".log")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
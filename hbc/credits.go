@@ -0,0 +1,172 @@
+package hbc
+
+import (
+	"io/fs"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	creditsFontSize    = 18
+	creditsHeadingSize = 24
+	creditsLineSpacing = 30
+	creditsSpeed       = 36
+	// creditsFadeHeight is how far from the top and bottom edges lines fade
+	// in and out.
+	creditsFadeHeight = 60
+)
+
+type CreditsConfig struct {
+	// AfterLoops rolls the credits every time this many loops have played.
+	// Zero only rolls them when the credits key is pressed.
+	AfterLoops int `json:"after_loops,omitempty"`
+	// Lines are added after the built-in credits. Lines starting with "# "
+	// are headings.
+	Lines []string `json:"lines,omitempty"`
+	// Speed is in pixels per second.
+	Speed float64 `json:"speed,omitempty"`
+}
+
+var defaultCredits = []string{
+	"# go-hbc-intro",
+	"by michioxd and contributors",
+	"",
+	"# Original banner",
+	"The Homebrew Channel",
+	"by fail0verflow",
+	"Artwork and music under the GNU GPL",
+	"",
+	"# Built with",
+	"Go and Ebitengine",
+}
+
+// creditsFile is an optional text file in an asset pack crediting its
+// authors, one line per entry, using the same headings as the config.
+const creditsFile = "credits.txt"
+
+// setupCredits asks for the credits each time AfterLoops more loops have
+// played.
+func (g *Intro) setupCredits() {
+	n := g.cfg.Credits.AfterLoops
+	if n <= 0 {
+		return
+	}
+	g.Subscribe(EventLoopWrapped, func(e Event) {
+		if e.Loop%n == 0 && !g.creditsRolling {
+			g.creditsRequested = true
+		}
+	})
+}
+
+func (g *Intro) creditLines() []string {
+	lines := append([]string(nil), defaultCredits...)
+	if data, err := fs.ReadFile(g.assets, creditsFile); err == nil {
+		lines = append(lines, "", "# Asset pack")
+		lines = append(lines, strings.Split(strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")...)
+	}
+	if len(g.cfg.Credits.Lines) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, g.cfg.Credits.Lines...)
+	}
+	return lines
+}
+
+// startCredits switches from the intro to the credits once they have been
+// asked for.
+func (a *App) startCredits() {
+	if a.scene != a.home || !a.game.creditsRequested {
+		return
+	}
+	a.game.creditsRequested = false
+	a.SetScene(newCreditsScene(a, a.game))
+}
+
+// creditsScene scrolls the credits over the water while the intro keeps
+// playing underneath, then returns to it. Pressing the credits key again
+// ends them early.
+type creditsScene struct {
+	app    *App
+	game   *Intro
+	lines  []string
+	offset float64
+	speed  float64
+}
+
+func newCreditsScene(app *App, g *Intro) *creditsScene {
+	speed := g.cfg.Credits.Speed
+	if speed <= 0 {
+		speed = creditsSpeed
+	}
+	g.creditsRolling = true
+	return &creditsScene{app: app, game: g, lines: g.creditLines(), speed: speed}
+}
+
+func (s *creditsScene) Update() error {
+	if err := s.game.Update(); err != nil {
+		return err
+	}
+	if !s.game.paused {
+		s.offset += s.speed / 60
+	}
+	height := float64(len(s.lines)) * creditsLineSpacing
+	if s.game.creditsRequested || s.offset > s.game.height+height {
+		s.game.creditsRequested = false
+		s.game.creditsRolling = false
+		s.app.SetScene(s.app.home)
+	}
+	return nil
+}
+
+// Draw shows the water without the title, which the credits would run
+// through, and the overlays on top of the text.
+func (s *creditsScene) Draw(screen *ebiten.Image) {
+	g := s.game
+	for _, l := range g.layers {
+		if !l.layer.overlay() && l.layer != LayerTitle {
+			g.blend = g.layerBlends[l.layer]
+			l.draw(screen)
+		}
+	}
+	g.blend = ebiten.Blend{}
+
+	// Screenshots include the credits but not the overlays.
+	ui := g.uiLayer(screen)
+	s.drawText(ui)
+	g.flushUILayer(screen, ui)
+	g.takeScreenshot(screen)
+
+	ui = g.uiLayer(screen)
+	for _, l := range g.layers {
+		if l.layer.overlay() {
+			l.draw(ui)
+		}
+	}
+	g.flushUILayer(screen, ui)
+}
+
+func (s *creditsScene) drawText(ui *ebiten.Image) {
+	g := s.game
+	body, heading := uiFace(creditsFontSize), uiFace(creditsHeadingSize)
+	for i, line := range s.lines {
+		y := g.height + float64(i)*creditsLineSpacing - s.offset
+		if y < -creditsLineSpacing || y > g.height {
+			continue
+		}
+		face := body
+		if h, ok := strings.CutPrefix(line, "# "); ok {
+			line, face = h, heading
+		}
+		edge := min(y, g.height-y-creditsLineSpacing)
+		alpha := min(max(edge/creditsFadeHeight, 0), 1)
+
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(g.width/2, y)
+		op.PrimaryAlign = text.AlignCenter
+		op.ColorScale.ScaleAlpha(float32(alpha))
+		text.Draw(ui, line, face, op)
+	}
+}
+
+func (s *creditsScene) drawsThroughView() {}
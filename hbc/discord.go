@@ -0,0 +1,231 @@
+package hbc
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Discord only accepts an activity update every 15 seconds.
+const discordUpdateInterval = 15 * time.Second
+
+const (
+	discordOpHandshake = 0
+	discordOpFrame     = 1
+	discordOpClose     = 2
+)
+
+type DiscordConfig struct {
+	Enabled bool `json:"enabled"`
+	// ClientID is the application ID of a Discord application, created at
+	// https://discord.com/developers/applications. Its name is what Discord
+	// shows as "Playing ...".
+	ClientID string `json:"client_id"`
+}
+
+// presence is the part of the playback state shown in Discord.
+type presence struct {
+	loop int
+	// start is when the intro would have started had it never been paused,
+	// worked out when the update is published so that the time it waits to
+	// be sent does not hold Discord's timer back.
+	start  time.Time
+	paused bool
+}
+
+// discordClient speaks Discord's local RPC protocol: little endian opcode and
+// length headers followed by a JSON payload, over a Unix socket or a named
+// pipe.
+type discordClient struct {
+	conn  io.ReadWriteCloser
+	nonce int
+}
+
+func connectDiscord(clientID string) (*discordClient, error) {
+	conn, err := dialDiscord()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &discordClient{conn: conn}
+	if err := c.send(discordOpHandshake, map[string]any{"v": 1, "client_id": clientID}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if _, err := c.recv(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *discordClient) send(op uint32, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 8+len(data))
+	binary.LittleEndian.PutUint32(buf[0:], op)
+	binary.LittleEndian.PutUint32(buf[4:], uint32(len(data)))
+	copy(buf[8:], data)
+	_, err = c.conn.Write(buf)
+	return err
+}
+
+func (c *discordClient) recv() ([]byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(c.conn, header[:]); err != nil {
+		return nil, err
+	}
+	op := binary.LittleEndian.Uint32(header[0:])
+	data := make([]byte, binary.LittleEndian.Uint32(header[4:]))
+	if _, err := io.ReadFull(c.conn, data); err != nil {
+		return nil, err
+	}
+	if op == discordOpClose {
+		return nil, fmt.Errorf("discord closed the connection: %s", data)
+	}
+	return data, nil
+}
+
+func (c *discordClient) setActivity(p presence) error {
+	state := "Loop #" + strconv.Itoa(p.loop+1)
+	if p.paused {
+		state += " (paused)"
+	}
+	activity := map[string]any{
+		"details": "Watching the HBC intro",
+		"state":   state,
+	}
+	// Discord counts the timer up from the start timestamp, so shifting it
+	// by the animation clock keeps it in sync with pauses.
+	if !p.paused {
+		activity["timestamps"] = map[string]any{
+			"start": p.start.Unix(),
+		}
+	}
+
+	c.nonce++
+	err := c.send(discordOpFrame, map[string]any{
+		"cmd":   "SET_ACTIVITY",
+		"nonce": strconv.Itoa(c.nonce),
+		"args": map[string]any{
+			"pid":      os.Getpid(),
+			"activity": activity,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := c.recv()
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Evt  string `json:"evt"`
+		Data struct {
+			Message string `json:"message"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err == nil && resp.Evt == "ERROR" {
+		return errors.New(resp.Data.Message)
+	}
+	return nil
+}
+
+func (c *discordClient) close() {
+	c.conn.Close()
+}
+
+// runDiscordPresence forwards presence updates to Discord until updates is
+// closed, reconnecting whenever Discord is restarted. Updates arriving faster
+// than Discord allows are coalesced.
+func runDiscordPresence(clientID string, updates <-chan presence) {
+	var (
+		client   *discordClient
+		latest   presence
+		pending  bool
+		lastSent time.Time
+		warned   bool
+	)
+	ticker := time.NewTicker(discordUpdateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case p, ok := <-updates:
+			if !ok {
+				if client != nil {
+					client.close()
+				}
+				return
+			}
+			latest, pending = p, true
+		case <-ticker.C:
+		}
+
+		if !pending || time.Since(lastSent) < discordUpdateInterval {
+			continue
+		}
+
+		if client == nil {
+			c, err := connectDiscord(clientID)
+			if err != nil {
+				if !warned {
+					log.Printf("Warning: Could not connect to Discord: %v\n", err)
+					warned = true
+				}
+				continue
+			}
+			client, warned = c, false
+		}
+
+		if err := client.setActivity(latest); err != nil {
+			log.Printf("Warning: Could not update Discord presence: %v\n", err)
+			client.close()
+			client = nil
+			continue
+		}
+		pending = false
+		lastSent = time.Now()
+	}
+}
+
+// publishPresence sends the playback state to the Discord goroutine,
+// replacing an update it has not picked up yet. The start time is only needed
+// to anchor Discord's own timer, so the events that change the loop number or
+// pause state are enough.
+func (g *Intro) publishPresence() {
+	p := presence{
+		loop:   g.loopCount,
+		start:  time.Now().Add(-time.Duration(g.ticks) * time.Second / 60),
+		paused: g.paused,
+	}
+	select {
+	case <-g.presence:
+	default:
+	}
+	g.presence <- p
+}
+
+func (g *Intro) startDiscordPresence(cfg DiscordConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	if cfg.ClientID == "" {
+		log.Printf("Warning: Discord presence is enabled but no client_id is set\n")
+		return
+	}
+	g.presence = make(chan presence, 1)
+	for _, kind := range []EventKind{EventIntroStarted, EventLoopWrapped, EventPaused, EventResumed} {
+		g.Subscribe(kind, func(Event) { g.publishPresence() })
+	}
+	go runDiscordPresence(cfg.ClientID, g.presence)
+}
@@ -0,0 +1,10 @@
+package hbc
+
+import (
+	"errors"
+	"io"
+)
+
+func dialDiscord() (io.ReadWriteCloser, error) {
+	return nil, errors.New("Discord presence is not available in the browser")
+}
@@ -0,0 +1,32 @@
+//go:build !windows && !js
+
+package hbc
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+func dialDiscord() (io.ReadWriteCloser, error) {
+	dir := os.TempDir()
+	for _, env := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
+		if v := os.Getenv(env); v != "" {
+			dir = v
+			break
+		}
+	}
+
+	err := errors.New("no Discord IPC socket found")
+	for i := 0; i < 10; i++ {
+		conn, dialErr := net.Dial("unix", filepath.Join(dir, "discord-ipc-"+strconv.Itoa(i)))
+		if dialErr == nil {
+			return conn, nil
+		}
+		err = dialErr
+	}
+	return nil, err
+}
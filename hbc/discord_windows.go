@@ -0,0 +1,20 @@
+package hbc
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+)
+
+func dialDiscord() (io.ReadWriteCloser, error) {
+	err := errors.New("no Discord IPC pipe found")
+	for i := 0; i < 10; i++ {
+		pipe, openErr := os.OpenFile(`\\.\pipe\discord-ipc-`+strconv.Itoa(i), os.O_RDWR, 0)
+		if openErr == nil {
+			return pipe, nil
+		}
+		err = openErr
+	}
+	return nil, err
+}
@@ -0,0 +1,213 @@
+package hbc
+
+import (
+	"io"
+	"io/fs"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Typing the Konami code turns the intro upside down for as long as one
+// loop lasts: the colors are inverted, bubbles pour out and everything,
+// music included, runs faster.
+
+var konamiCode = []ebiten.Key{
+	ebiten.KeyArrowUp, ebiten.KeyArrowUp, ebiten.KeyArrowDown, ebiten.KeyArrowDown,
+	ebiten.KeyArrowLeft, ebiten.KeyArrowRight, ebiten.KeyArrowLeft, ebiten.KeyArrowRight,
+	ebiten.KeyB, ebiten.KeyA,
+}
+
+const (
+	// partySpeed is how much faster the animation and music run. Every
+	// partyStep ticks the animation advances one extra frame, which must
+	// match it.
+	partySpeed = 1.25
+	partyStep  = 4
+	// partyBubbles are released every partyBubbleTicks.
+	partyBubbles     = 3
+	partyBubbleTicks = 4
+)
+
+// invertBlend draws white as one minus the destination, leaving its alpha.
+var invertBlend = ebiten.Blend{
+	BlendFactorSourceRGB:        ebiten.BlendFactorOneMinusDestinationColor,
+	BlendFactorSourceAlpha:      ebiten.BlendFactorZero,
+	BlendFactorDestinationRGB:   ebiten.BlendFactorZero,
+	BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+	BlendOperationRGB:           ebiten.BlendOperationAdd,
+	BlendOperationAlpha:         ebiten.BlendOperationAdd,
+}
+
+type partyMode struct {
+	// progress is how many keys of the code have been typed so far.
+	progress int
+	// frames is how many animation frames are left; zero when off.
+	frames int
+
+	// player plays the sped-up loop music in place of the loop player. Its
+	// stream is decoded again on its own goroutine, since the loop player
+	// is reading the first one.
+	player *audio.Player
+	stream chan io.ReadSeeker
+}
+
+func (g *Intro) updateKonamiCode() {
+	if g.kiosk {
+		return
+	}
+	p := &g.party
+	for _, key := range inpututil.AppendJustPressedKeys(nil) {
+		switch {
+		case key == konamiCode[p.progress]:
+			// Once the code is under way its keys are taken, so that B and A
+			// do not also release a burst and open the app grid.
+			if p.progress > 0 {
+				g.input.take(key)
+			}
+			p.progress++
+		case key == konamiCode[0]:
+			// Up, up, up still leaves two ups typed.
+			if p.progress != 2 {
+				p.progress = 1
+			}
+		default:
+			p.progress = 0
+		}
+		if p.progress == len(konamiCode) {
+			p.progress = 0
+			g.startParty()
+		}
+	}
+}
+
+func (g *Intro) startParty() {
+	p := &g.party
+	if p.frames > 0 {
+		return
+	}
+	p.frames = loopEnd - loopStart
+	g.flashFrames = flashFrames
+	if g.silent || g.offline || g.audioContext == nil || g.loopPlayer == nil || p.stream != nil {
+		return
+	}
+
+	p.stream = make(chan io.ReadSeeker, 1)
+	go func() {
+		s, err := fastLoopStream(g.assets, partySpeed)
+		if err != nil {
+			log.Printf("Warning: Could not speed up loop music: %v\n", err)
+		}
+		p.stream <- s
+	}()
+}
+
+// updateParty runs before the frame counter advances. The extra frame it
+// adds is one more that emitMilestones and the loop wrap in Update account
+// for, the same as the frames catchUp skips.
+func (g *Intro) updateParty() {
+	p := &g.party
+	if p.frames <= 0 {
+		return
+	}
+
+	if p.stream != nil {
+		select {
+		case s := <-p.stream:
+			p.stream = nil
+			g.startPartyMusic(s)
+		default:
+		}
+	}
+
+	if g.ticks%partyBubbleTicks == 0 {
+		for range partyBubbles {
+			g.spawnBubble(-1, "")
+		}
+	}
+
+	p.frames--
+	if g.ticks%partyStep == 0 {
+		g.count++
+		p.frames--
+	}
+	if p.frames <= 0 {
+		p.frames = 0
+		g.stopPartyMusic()
+	}
+}
+
+// startPartyMusic swaps the loop player for one playing s, from the same
+// place in the music.
+func (g *Intro) startPartyMusic(s io.ReadSeeker) {
+	p := &g.party
+	if s == nil || p.frames <= 0 || !g.loopPlayer.IsPlaying() {
+		return
+	}
+	player, err := g.audioContext.NewPlayer(s)
+	if err != nil {
+		log.Printf("Warning: Could not create audio player: %v\n", err)
+		return
+	}
+	g.setBufferSize(player)
+	g.loopPlayer.Pause()
+	if err := player.SetPosition(scaleDuration(g.loopPlayer.Position(), 1/partySpeed)); err != nil {
+		log.Printf("Warning: Could not seek loop music: %v\n", err)
+	}
+	p.player = player
+	g.setVolume(g.baseVolume())
+	player.Play()
+}
+
+func (g *Intro) stopPartyMusic() {
+	p := &g.party
+	if p.player == nil {
+		return
+	}
+	pos := p.player.Position()
+	p.player.Pause()
+	if err := p.player.Close(); err != nil {
+		log.Printf("Warning: Could not close audio player: %v\n", err)
+	}
+	p.player = nil
+	if err := g.loopPlayer.SetPosition(scaleDuration(pos, partySpeed)); err != nil {
+		log.Printf("Warning: Could not seek loop music: %v\n", err)
+	}
+}
+
+// fastLoopStream decodes the loop music again and resamples it so that it
+// plays speed times as fast, looping like the original.
+func fastLoopStream(fsys fs.FS, speed float64) (io.ReadSeeker, error) {
+	dec, aerr := decodeAudio(fsys, findAudio(fsys, audioPaths["loop"]))
+	if aerr != nil {
+		return nil, aerr
+	}
+	from := int(sampleRate * speed)
+	scale := func(n int64) int64 {
+		return int64(float64(n)*sampleRate/float64(from)) &^ 3
+	}
+	length := scale(dec.Length())
+	fast := audio.Resample(dec, dec.Length(), from, sampleRate)
+	if l, ok := dec.(loopedStream); ok {
+		start, n := l.Loop()
+		return audio.NewInfiniteLoopWithIntro(fast, scale(start), min(scale(n), length-scale(start))), nil
+	}
+	return audio.NewInfiniteLoop(fast, length), nil
+}
+
+func (g *Intro) drawParty(screen *ebiten.Image) {
+	if g.party.frames <= 0 {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(g.width, g.height)
+	op.Blend = invertBlend
+	g.drawImage(screen, g.texture("white.png"), op)
+}
+
+func scaleDuration(d time.Duration, f float64) time.Duration {
+	return time.Duration(float64(d) * f)
+}
@@ -0,0 +1,101 @@
+package hbc
+
+import (
+	"log"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Effect is a custom addition to the intro that runs inside its loop, next
+// to the built-in waves, bubbles and overlays.
+type Effect interface {
+	// Init is called once the assets have loaded. An effect that fails to
+	// initialize is left out.
+	Init(in *Intro) error
+	// Update is called once per tick while the intro is not paused. An error
+	// stops the intro.
+	Update(in *Intro) error
+	// Draw is called with the image for the effect's layer. Animation layers
+	// are drawn at the render resolution; concatenate in.View() to position
+	// things in 810x456 coordinates. Overlay layers are always 810x456.
+	Draw(screen *ebiten.Image, in *Intro)
+	// Layer is where the effect is drawn, above whatever was already added
+	// to that layer.
+	Layer() Layer
+}
+
+var (
+	effectsMu sync.Mutex
+	effects   []func() Effect
+)
+
+// RegisterEffect adds an effect to every intro created after the call. It is
+// meant to be called from init functions, so that importing a package is
+// enough to enable its effects.
+func RegisterEffect(newEffect func() Effect) {
+	effectsMu.Lock()
+	defer effectsMu.Unlock()
+	effects = append(effects, newEffect)
+}
+
+func (g *Intro) setupEffects() {
+	effectsMu.Lock()
+	defer effectsMu.Unlock()
+	for _, newEffect := range effects {
+		g.AddEffect(newEffect())
+	}
+}
+
+// AddEffect adds an effect to this intro only. It must not be called while
+// the intro is running.
+func (g *Intro) AddEffect(e Effect) {
+	// Effects are told apart by their index rather than compared, which
+	// would panic for effects of a type that is not comparable.
+	i := len(g.effects)
+	g.effects = append(g.effects, e)
+	g.failedEffects = append(g.failedEffects, false)
+	g.addLayer(e.Layer(), func(screen *ebiten.Image) {
+		if g.effectReady(i) {
+			e.Draw(screen, g)
+		}
+	})
+	if g.loaded {
+		g.initEffect(i)
+	}
+}
+
+func (g *Intro) initEffect(i int) {
+	e := g.effects[i]
+	if err := e.Init(g); err != nil {
+		log.Printf("Warning: Could not initialize effect %T: %v\n", e, err)
+		g.failedEffects[i] = true
+	}
+}
+
+func (g *Intro) effectReady(i int) bool {
+	return g.loaded && !g.failedEffects[i]
+}
+
+func (g *Intro) initEffects() {
+	for i := range g.effects {
+		g.initEffect(i)
+	}
+}
+
+func (g *Intro) updateEffects() error {
+	for i, e := range g.effects {
+		if !g.effectReady(i) {
+			continue
+		}
+		if err := e.Update(g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// View is the transform from the 810x456 layout to the render resolution.
+func (g *Intro) View() ebiten.GeoM {
+	return g.view
+}
@@ -0,0 +1,49 @@
+package hbc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// sliceEffect is an effect whose type cannot be compared, since it holds a
+// slice. It fails to initialize when fail is set and counts its updates.
+type sliceEffect struct {
+	fail    bool
+	updates []int
+	count   *int
+}
+
+func (e sliceEffect) Init(in *Intro) error {
+	if e.fail {
+		return errors.New("no assets")
+	}
+	return nil
+}
+
+func (e sliceEffect) Update(in *Intro) error {
+	*e.count++
+	return nil
+}
+
+func (e sliceEffect) Draw(screen *ebiten.Image, in *Intro) {}
+
+func (e sliceEffect) Layer() Layer { return LayerOverlay }
+
+// TestFailedEffectIsLeftOut checks that an effect that fails to initialize
+// is skipped without affecting the others, whatever their type.
+func TestFailedEffectIsLeftOut(t *testing.T) {
+	g := newTestIntro(t, 1)
+	g.loaded = true
+	var failed, ok int
+	g.AddEffect(sliceEffect{fail: true, count: &failed})
+	g.AddEffect(sliceEffect{updates: []int{1}, count: &ok})
+
+	if err := g.updateEffects(); err != nil {
+		t.Fatal(err)
+	}
+	if failed != 0 || ok != 1 {
+		t.Errorf("updated the failed effect %d times and the other %d times, want 0 and 1", failed, ok)
+	}
+}
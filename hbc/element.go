@@ -0,0 +1,99 @@
+package hbc
+
+import (
+	"log"
+	"slices"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ElementSpec is a custom decoration that moves like the wave layers: it
+// rises with the water during the opening, sways and follows the tilt
+// parallax. Lengths are in the 810x456 layout.
+type ElementSpec struct {
+	// Texture is the image's path under img/ in the asset pack.
+	Texture       string
+	Width, Height float64
+	// X is the offset of the element's centre from the middle of the
+	// screen, and Y its offset below the water surface.
+	X, Y float64
+	// Rotation is in radians. A Scale of 0 is the same as 1.
+	Rotation, Scale float64
+	// Loop repeats the element sideways across the whole width.
+	Loop bool
+	// SwayX and SwayY are how far the element sways, and SpeedX and SpeedY
+	// how fast. The slowest wave layer has a speed of 1 and sways 200 by 5.
+	SwayX, SwayY   float64
+	SpeedX, SpeedY float64
+	// Depth places the element for the parallax, from 0 at the back to 1 at
+	// the front.
+	Depth float64
+	// Layer is one of the animation layers, up to LayerFlash. The element is
+	// drawn above what that layer already holds, so LayerWaves puts it in
+	// front of the built-in waves.
+	Layer Layer
+}
+
+var (
+	elementsMu sync.Mutex
+	elements   []ElementSpec
+)
+
+// RegisterElement adds an element to every intro created after the call,
+// like RegisterEffect.
+func RegisterElement(spec ElementSpec) {
+	elementsMu.Lock()
+	defer elementsMu.Unlock()
+	elements = append(elements, spec)
+}
+
+func (g *Intro) setupElements() {
+	elementsMu.Lock()
+	defer elementsMu.Unlock()
+	for _, spec := range elements {
+		g.AddElement(spec)
+	}
+}
+
+// AddElement adds an element to this intro only. Its texture is loaded with
+// the others, so it must be called before the intro runs.
+func (g *Intro) AddElement(spec ElementSpec) {
+	if spec.Layer.overlay() {
+		log.Printf("Warning: Element %s cannot be drawn on the %s layer, using waves\n", spec.Texture, spec.Layer)
+		spec.Layer = LayerWaves
+	}
+	elem := Element{
+		name:       spec.Texture,
+		x:          spec.X,
+		y:          spec.Y,
+		width:      spec.Width,
+		height:     spec.Height,
+		rotation:   spec.Rotation,
+		scale:      spec.Scale,
+		loop:       spec.Loop,
+		animateX:   spec.SwayX != 0,
+		animateY:   spec.SwayY != 0,
+		animRangeX: spec.SwayX,
+		animRangeY: spec.SwayY,
+		animSpeedX: spec.SpeedX,
+		animSpeedY: spec.SpeedY,
+		depth:      spec.Depth,
+	}
+	g.customElements = append(g.customElements, elem)
+	g.addLayer(spec.Layer, func(screen *ebiten.Image) {
+		g.drawElement(screen, elem)
+	})
+}
+
+// textureList is every texture the intro draws from the asset pack: the
+// built-in ones and those of custom elements.
+func (g *Intro) textureList() []string {
+	names := texturePaths
+	for _, elem := range g.customElements {
+		if !slices.Contains(names, elem.name) {
+			names = append(slices.Clip(names), elem.name)
+		}
+	}
+	return names
+}
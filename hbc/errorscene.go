@@ -0,0 +1,51 @@
+package hbc
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// errorScene replaces the intro in strict mode when assets are missing or
+// broken, telling the user which files are wrong and where they belong.
+type errorScene struct {
+	game    *Intro
+	message string
+}
+
+func newAssetErrorScene(g *Intro, failures []*assetError) *errorScene {
+	var b strings.Builder
+	b.WriteString("Some assets could not be loaded:\n\n")
+	for _, f := range failures {
+		fmt.Fprintf(&b, "  %s (%s failed: %v)\n", f.path, f.op, f.err)
+	}
+	b.WriteString("\n")
+	if g.assetsSource == "" {
+		b.WriteString("Put the files under assets/ and rebuild, or pass -assets with a pack\n")
+		b.WriteString("(zip or directory) that contains img/*.png and audio/*.wav or *.flac.")
+	} else {
+		fmt.Fprintf(&b, "Add the files to %s, using the img/ and audio/ layout.", g.assetsSource)
+	}
+	b.WriteString("\n\nPress Esc to quit.")
+
+	return &errorScene{game: g, message: b.String()}
+}
+
+func (s *errorScene) Update() error {
+	if s.game.input.JustPressed(ActionQuit) || s.game.quitRequested.Load() || ebiten.IsWindowBeingClosed() {
+		return ebiten.Termination
+	}
+	return nil
+}
+
+func (s *errorScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{24, 24, 32, 255})
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(24, 24)
+	op.LineSpacing = 18
+	text.Draw(screen, s.message, uiFace(13), op)
+}
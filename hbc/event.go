@@ -0,0 +1,90 @@
+package hbc
+
+// EventKind identifies a milestone of the animation.
+type EventKind int
+
+const (
+	// EventIntroStarted is sent on the first tick.
+	EventIntroStarted EventKind = iota
+	// EventFlashPeak is sent on the frame the white flash is brightest.
+	EventFlashPeak
+	// EventTitleLanded is sent once the flash has faded and the title is
+	// fully visible.
+	EventTitleLanded
+	// EventLoopWrapped is sent when the animation jumps back to the start of
+	// the loop.
+	EventLoopWrapped
+	// EventBubbleBurst is sent when a burst of bubbles is released: the
+	// eruption as the water rises, each burst from the config and the burst
+	// key.
+	EventBubbleBurst
+	// EventPaused is sent when playback is paused, with the frame it stops on.
+	EventPaused
+	// EventResumed is sent when paused playback continues.
+	EventResumed
+)
+
+// Event is a milestone along with the playback state at the time.
+type Event struct {
+	Kind  EventKind
+	Frame int
+	Loop  int
+}
+
+// eventBus delivers events to subscribers on the game loop, in the order
+// they subscribed.
+type eventBus struct {
+	subs map[EventKind][]func(Event)
+}
+
+func (b *eventBus) subscribe(kind EventKind, fn func(Event)) {
+	if b.subs == nil {
+		b.subs = make(map[EventKind][]func(Event))
+	}
+	b.subs[kind] = append(b.subs[kind], fn)
+}
+
+func (b *eventBus) publish(e Event) {
+	for _, fn := range b.subs[e.Kind] {
+		fn(e)
+	}
+}
+
+// Subscribe calls fn on the game loop every time an event of the given kind
+// happens. It must not be called while the intro is running.
+func (g *Intro) Subscribe(kind EventKind, fn func(Event)) {
+	g.events.subscribe(kind, fn)
+}
+
+func (g *Intro) emit(kind EventKind) {
+	g.events.publish(Event{Kind: kind, Frame: g.count, Loop: g.loopCount})
+}
+
+// emitMilestones sends the events for the frames reached since from. It
+// runs once per tick after the frame counter has advanced, which is usually
+// by one frame but by more when catchUp skips ahead; wrapped tells whether
+// the loop started over on the way.
+func (g *Intro) emitMilestones(from int, wrapped bool) {
+	reached := func(frame int) bool {
+		if wrapped {
+			return frame > from || frame >= loopStart && frame <= g.count
+		}
+		return frame > from && frame <= g.count
+	}
+	if reached(g.timeline.flash) {
+		g.emit(EventFlashPeak)
+	}
+	if reached(g.timeline.titleLanded()) {
+		g.emit(EventTitleLanded)
+	}
+	// Scripted bubbles are laid out ahead of time, so their bursts are sent
+	// as the frames they start on are reached.
+	if reached(g.timeline.bubbles) {
+		g.emit(EventBubbleBurst)
+	}
+	for _, b := range g.cfg.Bubbles.Bursts {
+		if reached(b.Frame) {
+			g.emit(EventBubbleBurst)
+		}
+	}
+}
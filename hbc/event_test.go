@@ -0,0 +1,79 @@
+package hbc
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// recordMilestones collects the kinds of the flash and title events g
+// sends.
+func recordMilestones(g *Intro) *[]EventKind {
+	var got []EventKind
+	for _, kind := range []EventKind{EventFlashPeak, EventTitleLanded} {
+		g.Subscribe(kind, func(e Event) { got = append(got, e.Kind) })
+	}
+	return &got
+}
+
+// TestMilestonesAfterStall checks that when catchUp skips the frames of a
+// stall, the milestones on those frames are still sent, in order.
+func TestMilestonesAfterStall(t *testing.T) {
+	g := newTestIntro(t, 1)
+	g.offline, g.loaded = false, true
+	got := recordMilestones(g)
+
+	g.count = g.timeline.flash - 1
+	stall := g.timeline.titleLanded() - g.count + maxLagFrames
+	g.clockStart, g.clockTicks = time.Now().Add(-time.Duration(stall)*time.Second/60), 0
+	if err := g.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if g.count <= g.timeline.titleLanded() {
+		t.Fatalf("frame %d after the stall, want past the title landing on %d", g.count, g.timeline.titleLanded())
+	}
+	if want := []EventKind{EventFlashPeak, EventTitleLanded}; !slices.Equal(*got, want) {
+		t.Errorf("sent %v, want %v", *got, want)
+	}
+}
+
+// TestMilestonesInPartyMode checks that the extra frame the Konami code's
+// party adds does not skip a milestone.
+func TestMilestonesInPartyMode(t *testing.T) {
+	g := newTestIntro(t, 1)
+	g.loaded = true
+	got := recordMilestones(g)
+
+	g.startParty()
+	g.count, g.ticks = g.timeline.flash-1, 0
+	if err := g.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if g.count != g.timeline.flash+1 {
+		t.Fatalf("frame %d after a party tick, want %d", g.count, g.timeline.flash+1)
+	}
+	if want := []EventKind{EventFlashPeak}; !slices.Equal(*got, want) {
+		t.Errorf("sent %v, want %v", *got, want)
+	}
+}
+
+// TestBurstEvents checks that the eruption and the bursts from the config
+// are sent as they are reached, those in the loop on every loop.
+func TestBurstEvents(t *testing.T) {
+	g := newTestIntro(t, 1)
+	g.cfg.Bubbles.Bursts = []BurstConfig{{Frame: loopStart + 10}}
+	var bursts []int
+	g.Subscribe(EventBubbleBurst, func(e Event) { bursts = append(bursts, e.Frame) })
+
+	g.count = g.timeline.bubbles
+	g.emitMilestones(g.count-1, false)
+	g.count = loopStart + 20
+	g.emitMilestones(loopStart+5, false)
+	// Skipping across the seam reaches the burst of the next loop too.
+	g.count = loopStart + 15
+	g.emitMilestones(loopEnd-5, true)
+
+	if want := []int{g.timeline.bubbles, loopStart + 20, loopStart + 15}; !slices.Equal(bursts, want) {
+		t.Errorf("bursts sent on frames %v, want %v", bursts, want)
+	}
+}
@@ -0,0 +1,129 @@
+package hbc
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+var (
+	waterTop    = color.RGBA{65, 145, 176, 255}
+	waterBack   = color.RGBA{75, 157, 188, 255}
+	waterBottom = color.RGBA{47, 126, 156, 255}
+)
+
+// waveShape is the water of a wave texture, filled below a sine crest. The
+// crest repeats a whole number of times across the width, so it tiles.
+type waveShape struct {
+	width, height    int
+	crest, amplitude float64
+	phase            float64
+	color            color.RGBA
+}
+
+func (s waveShape) edge(x float64) float64 {
+	return s.crest + s.amplitude*math.Sin(2*math.Pi*4*x/float64(s.width)+s.phase)
+}
+
+// foamShape is a faint white band swaying over cycles sine periods and
+// thinning out at both ends.
+type foamShape struct {
+	width, height int
+	cycles, alpha float64
+}
+
+// band returns the middle and half thickness of the band at x.
+func (s foamShape) band(x float64) (mid, half float64) {
+	t := x / float64(s.width)
+	h := float64(s.height)
+	return h/2 + h/5*math.Sin(2*math.Pi*s.cycles*t), h / 6 * math.Sin(math.Pi*t)
+}
+
+var (
+	waveShapes = map[string]waveShape{
+		"banner_wavea.png": {1024, 64, 28, 4, 0, waterTop},
+		"banner_waveb.png": {1024, 64, 26, 5, math.Pi / 2, waterBack},
+	}
+	foamShapes = map[string]foamShape{
+		"banner_wave1a.png": {382, 32, 2, 0.1},
+		"banner_wave1b.png": {527, 37, 3, 0.1},
+		"banner_shape2.png": {644, 28, 1, 0.27},
+	}
+)
+
+// fallbackTexture approximates a missing texture, at the size of the
+// original, so that a build without assets still looks like the intro.
+// Bubble textures are drawn by drawBubbleTextures instead.
+func fallbackTexture(name string) *ebiten.Image {
+	if s, ok := waveShapes[name]; ok {
+		return ebiten.NewImageFromImage(waveTexture(s))
+	}
+	if s, ok := foamShapes[name]; ok {
+		return ebiten.NewImageFromImage(foamTexture(s))
+	}
+	switch name {
+	case "white.png":
+		img := ebiten.NewImage(1, 1)
+		img.Fill(color.White)
+		return img
+	case "banner_fade.png":
+		return ebiten.NewImageFromImage(verticalGradient(256, waterBack, waterBottom))
+	case "banner_title.png":
+		return titleTexture()
+	}
+	return nil
+}
+
+func verticalGradient(h int, top, bottom color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 1, h))
+	for y := range h {
+		t := float64(y) / float64(h-1)
+		mix := func(a, b uint8) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*t) }
+		img.SetRGBA(0, y, color.RGBA{mix(top.R, bottom.R), mix(top.G, bottom.G), mix(top.B, bottom.B), 255})
+	}
+	return img
+}
+
+func waveTexture(s waveShape) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, s.width, s.height))
+	for x := range s.width {
+		top := s.edge(float64(x))
+		for y := range s.height {
+			a := min(max(float64(y)+1-top, 0), 1)
+			img.SetRGBA(x, y, premultiply(s.color, a))
+		}
+	}
+	return img
+}
+
+func foamTexture(s foamShape) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, s.width, s.height))
+	for x := range s.width {
+		mid, half := s.band(float64(x))
+		for y := range s.height {
+			a := min(max(half-math.Abs(float64(y)-mid), 0), 1)
+			img.SetRGBA(x, y, premultiply(color.RGBA{255, 255, 255, 255}, s.alpha*a))
+		}
+	}
+	return img
+}
+
+func premultiply(c color.RGBA, a float64) color.RGBA {
+	return color.RGBA{uint8(float64(c.R) * a), uint8(float64(c.G) * a), uint8(float64(c.B) * a), uint8(255 * a)}
+}
+
+// titleTexture writes the channel's name where the banner would be.
+func titleTexture() *ebiten.Image {
+	img := ebiten.NewImage(400, 180)
+	face := uiFace(52)
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(200, 90)
+	op.PrimaryAlign = text.AlignCenter
+	op.SecondaryAlign = text.AlignCenter
+	op.LineSpacing = 56
+	text.Draw(img, "Homebrew\nChannel", face, op)
+	return img
+}
@@ -0,0 +1,34 @@
+package hbc
+
+import (
+	"bytes"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+var uiFontSource = func() *text.GoTextFaceSource {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+	if err != nil {
+		log.Fatalf("Could not load UI font: %v", err)
+	}
+	return src
+}()
+
+func uiFace(size float64) *text.GoTextFace {
+	return &text.GoTextFace{Source: uiFontSource, Size: size}
+}
+
+var monoFontSource = func() *text.GoTextFaceSource {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(gomono.TTF))
+	if err != nil {
+		log.Fatalf("Could not load monospaced font: %v", err)
+	}
+	return src
+}()
+
+func monoFace(size float64) *text.GoTextFace {
+	return &text.GoTextFace{Source: monoFontSource, Size: size}
+}
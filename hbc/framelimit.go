@@ -0,0 +1,51 @@
+package hbc
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// frameLimitSlack lets a frame be drawn this early, so that a limit that
+// divides the display's refresh rate does not drop frames to timer jitter.
+const frameLimitSlack = 2 * time.Millisecond
+
+// frameLimiter caps how often the window is drawn, for e-ink panels and
+// remote desktops that cannot keep up with 60 frames a second. Update is not
+// affected, so the animation and music keep their speed.
+type frameLimiter struct {
+	fps atomic.Int32
+	// next is when the next frame is due. Only the game loop uses it.
+	next time.Time
+}
+
+// skip reports whether the frame drawn at now should be left out, keeping
+// the previous one on screen.
+func (l *frameLimiter) skip(now time.Time) bool {
+	fps := l.fps.Load()
+	if fps <= 0 {
+		return false
+	}
+	if now.Before(l.next.Add(-frameLimitSlack)) {
+		return true
+	}
+	interval := time.Second / time.Duration(fps)
+	l.next = l.next.Add(interval)
+	// Start over after a stall or a raised limit rather than drawing every
+	// frame to catch up.
+	if l.next.Before(now) {
+		l.next = now.Add(interval)
+	}
+	return false
+}
+
+// SetFrameLimit caps how many frames a second the window is drawn, while the
+// animation keeps running at 60 ticks a second. Zero or less removes the cap.
+// It is safe to call from any goroutine.
+func (g *Intro) SetFrameLimit(fps int) {
+	g.frameLimit.fps.Store(int32(max(fps, 0)))
+}
+
+// FrameLimit is the cap set by SetFrameLimit or the config, or zero.
+func (g *Intro) FrameLimit() int {
+	return int(g.frameLimit.fps.Load())
+}
@@ -0,0 +1,106 @@
+package hbc
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Gallery shows every loaded texture one at a time, for people checking a
+// custom asset pack.
+type Gallery struct {
+	game  *Intro
+	names []string
+	refs  map[string][]string
+	index int
+}
+
+func NewGallery(g *Intro) *Gallery {
+	// Lazily loaded textures are listed before they are first shown.
+	names := slices.Clone(g.textureList())
+	for name := range g.textures {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	return &Gallery{
+		game:  g,
+		names: names,
+		refs:  g.textureRefs(),
+	}
+}
+
+// textureRefs maps each texture name to the elements that draw it.
+func (g *Intro) textureRefs() map[string][]string {
+	refs := map[string][]string{
+		"banner_title.png": {"title"},
+		"white.png":        {"background", "flash"},
+		"banner_fade.png":  {"fade"},
+	}
+	for i, elem := range g.waveElements {
+		refs[elem.name] = append(refs[elem.name], fmt.Sprintf("wave layer %d", i))
+	}
+	for i, elem := range g.customElements {
+		refs[elem.name] = append(refs[elem.name], fmt.Sprintf("custom element %d", i))
+	}
+	for i, bt := range g.bubbleTypes {
+		refs[bt.name] = append(refs[bt.name], fmt.Sprintf("bubble type %d", i))
+	}
+	return refs
+}
+
+func (gl *Gallery) Update() error {
+	if gl.game.input.JustPressed(ActionQuit) || gl.game.quitRequested.Load() || ebiten.IsWindowBeingClosed() {
+		return ebiten.Termination
+	}
+	if len(gl.names) == 0 {
+		return nil
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		gl.index = (gl.index + 1) % len(gl.names)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		gl.index = (gl.index + len(gl.names) - 1) % len(gl.names)
+	}
+	return nil
+}
+
+func (gl *Gallery) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{48, 48, 48, 255})
+	if len(gl.names) == 0 {
+		ebitenutil.DebugPrint(screen, "No textures loaded")
+		return
+	}
+
+	name := gl.names[gl.index]
+	img := gl.game.texture(name)
+	w, h := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+
+	maxW, maxH := screenWidth*0.8, screenHeight*0.6
+	scale := min(maxW/w, maxH/h, 4)
+	dw, dh := w*scale, h*scale
+	x, y := (screenWidth-dw)/2, (screenHeight-dh)/2
+
+	vector.StrokeRect(screen, float32(x)-1, float32(y)-1, float32(dw)+2, float32(dh)+2, 1, color.RGBA{128, 128, 128, 255}, false)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(x, y)
+	screen.DrawImage(img, op)
+
+	refs := "unused"
+	if r := gl.refs[name]; len(r) > 0 {
+		refs = strings.Join(r, ", ")
+	}
+	info := fmt.Sprintf("%d/%d  %s\n%dx%d (shown at %0.0f%%)\nUsed by: %s\n\nLeft/Right: browse  Esc: quit",
+		gl.index+1, len(gl.names), name, int(w), int(h), scale*100, refs)
+	ebitenutil.DebugPrintAt(screen, info, 8, 8)
+}
@@ -0,0 +1,49 @@
+package hbc
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// runHeadless plays frames ticks of the intro's real Update without opening
+// a window or the audio output, for smoke tests in CI. The intro is created
+// with Config.NoAudio, so no sound device is needed either. Every asset has
+// to load. With draw, each tick is also drawn offscreen; the GPU is never
+// started, so this exercises the drawing code rather than the pixels. A
+// panic is returned as an error with its stack.
+func runHeadless(g *Intro, frames int, draw bool) (err error) {
+	g.prepareOffline()
+	g.setupTextureCache()
+	l := startAssetLoader(g.assets, g.eagerTextures(), g.renderScale)
+	l.wait()
+	if len(l.failures) > 0 {
+		return fmt.Errorf("%d of %d assets failed to load", len(l.failures), l.total)
+	}
+	g.finishLoading(l)
+
+	tick := 0
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic at tick %d (frame %d): %v\n%s", tick, g.count, r, debug.Stack())
+		}
+	}()
+
+	var canvas *ebiten.Image
+	if draw {
+		canvas = ebiten.NewImage(g.layoutSize())
+	}
+	for ; tick < frames; tick++ {
+		if err := g.Update(); err != nil {
+			return fmt.Errorf("update at tick %d (frame %d): %w", tick, g.count, err)
+		}
+		if canvas != nil {
+			canvas.Clear()
+			g.DrawTo(canvas)
+		}
+	}
+	log.Printf("Ran %d ticks headless, ending on frame %d of loop %d\n", frames, g.count, g.loopCount)
+	return nil
+}
@@ -0,0 +1,73 @@
+package hbc
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	helpStartupFrames = 5 * 60
+	helpFadeFrames    = 30
+	helpFontSize      = 14
+	helpLineSpacing   = 20
+	helpPadding       = 16
+)
+
+func (g *Intro) updateHelp() {
+	if g.input.JustPressed(ActionHelp) {
+		if g.helpVisible() {
+			g.helpTimer = 0
+			g.helpPinned = false
+		} else {
+			g.helpPinned = true
+		}
+	}
+	if g.helpTimer > 0 {
+		g.helpTimer--
+	}
+}
+
+func (g *Intro) helpVisible() bool {
+	return g.helpPinned || g.helpTimer > 0
+}
+
+func (g *Intro) helpText() string {
+	s := "Controls\n" + g.input.Help() + "\nSettings\n"
+	s += fmt.Sprintf("Muted: %s\n", onOff(g.muted))
+	s += fmt.Sprintf("Paused: %s\n", onOff(g.paused))
+	s += fmt.Sprintf("Fullscreen: %s\n", onOff(ebiten.IsFullscreen()))
+	s += fmt.Sprintf("Debug overlay: %s\n", onOff(g.debugMode))
+	s += fmt.Sprintf("Confirm quit: %s", onOff(g.confirmQuit))
+	return s
+}
+
+func (g *Intro) drawHelp(screen *ebiten.Image) {
+	if !g.helpVisible() {
+		return
+	}
+
+	alpha := 1.0
+	if !g.helpPinned && g.helpTimer < helpFadeFrames {
+		alpha = float64(g.helpTimer) / helpFadeFrames
+	}
+
+	face := uiFace(helpFontSize)
+	msg := g.helpText()
+	w, h := text.Measure(msg, face, helpLineSpacing)
+	w += helpPadding * 2
+	h += helpPadding * 2
+	x := (g.width - w) / 2
+	y := (g.height - h) / 2
+
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), color.RGBA{0, 0, 0, uint8(160 * alpha)}, false)
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x+helpPadding, y+helpPadding)
+	op.LineSpacing = helpLineSpacing
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	text.Draw(screen, msg, face, op)
+}
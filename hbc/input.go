@@ -0,0 +1,139 @@
+package hbc
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+type Action string
+
+const (
+	ActionDebug      Action = "debug"
+	ActionPause      Action = "pause"
+	ActionScreenshot Action = "screenshot"
+	ActionMute       Action = "mute"
+	ActionFullscreen Action = "fullscreen"
+	ActionQuit       Action = "quit"
+	ActionHelp       Action = "help"
+	ActionBurst      Action = "burst"
+	ActionFlash      Action = "flash"
+	ActionTheme      Action = "theme"
+	ActionSettings   Action = "settings"
+	ActionScaleUp    Action = "scale-up"
+	ActionScaleDown  Action = "scale-down"
+	ActionCredits    Action = "credits"
+	ActionApps       Action = "apps"
+	ActionMini       Action = "mini"
+)
+
+var actions = []struct {
+	action      Action
+	description string
+}{
+	{ActionDebug, "toggle debug overlay"},
+	{ActionPause, "pause"},
+	{ActionScreenshot, "save screenshot"},
+	{ActionMute, "mute"},
+	{ActionFullscreen, "toggle fullscreen"},
+	{ActionQuit, "quit"},
+	{ActionHelp, "show this help"},
+	{ActionBurst, "release a burst of bubbles"},
+	{ActionFlash, "flash the screen"},
+	{ActionTheme, "next theme"},
+	{ActionSettings, "open settings"},
+	{ActionScaleUp, "raise render resolution"},
+	{ActionScaleDown, "lower render resolution"},
+	{ActionCredits, "roll the credits"},
+	{ActionApps, "open or close the app grid"},
+	{ActionMini, "shrink to a floating mini window"},
+}
+
+func defaultKeyBindings() map[Action]ebiten.Key {
+	return map[Action]ebiten.Key{
+		ActionDebug:      ebiten.KeyD,
+		ActionPause:      ebiten.KeySpace,
+		ActionScreenshot: ebiten.KeyF12,
+		ActionMute:       ebiten.KeyM,
+		ActionFullscreen: ebiten.KeyF,
+		ActionQuit:       ebiten.KeyEscape,
+		ActionHelp:       ebiten.KeyH,
+		ActionBurst:      ebiten.KeyB,
+		ActionFlash:      ebiten.KeyL,
+		ActionTheme:      ebiten.KeyT,
+		ActionSettings:   ebiten.KeyF2,
+		ActionScaleUp:    ebiten.KeyBracketRight,
+		ActionScaleDown:  ebiten.KeyBracketLeft,
+		ActionCredits:    ebiten.KeyC,
+		ActionApps:       ebiten.KeyA,
+		ActionMini:       ebiten.KeyP,
+	}
+}
+
+// Input resolves actions to their bound keys. Press and release edges come
+// from inpututil, so they are reported exactly once per transition no matter
+// how long the key is held.
+type Input struct {
+	bindings map[Action]ebiten.Key
+	// taken holds the keys pressed this tick that were already used for
+	// something else, such as the Konami code, and trigger no action.
+	taken []ebiten.Key
+}
+
+func NewInput(bindings map[Action]ebiten.Key) *Input {
+	return &Input{bindings: bindings}
+}
+
+func (in *Input) Key(a Action) (ebiten.Key, bool) {
+	key, ok := in.bindings[a]
+	return key, ok
+}
+
+func (in *Input) Pressed(a Action) bool {
+	key, ok := in.Key(a)
+	return ok && ebiten.IsKeyPressed(key)
+}
+
+func (in *Input) JustPressed(a Action) bool {
+	key, ok := in.Key(a)
+	return ok && inpututil.IsKeyJustPressed(key) && !slices.Contains(in.taken, key)
+}
+
+// take keeps this tick's press of key from triggering its action.
+func (in *Input) take(key ebiten.Key) {
+	in.taken = append(in.taken, key)
+}
+
+// newTick forgets the keys taken in the last tick.
+func (in *Input) newTick() {
+	in.taken = in.taken[:0]
+}
+
+func (in *Input) JustReleased(a Action) bool {
+	key, ok := in.Key(a)
+	return ok && inpututil.IsKeyJustReleased(key)
+}
+
+// HeldFrames returns how many ticks the action's key has been held, or 0 if
+// it is up.
+func (in *Input) HeldFrames(a Action) int {
+	key, ok := in.Key(a)
+	if !ok {
+		return 0
+	}
+	return inpututil.KeyPressDuration(key)
+}
+
+func (in *Input) Help() string {
+	var s string
+	for _, a := range actions {
+		key, ok := in.Key(a.action)
+		if !ok {
+			continue
+		}
+		s += fmt.Sprintf("%-10s %s\n", key, a.description)
+	}
+	return s
+}
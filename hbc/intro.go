@@ -0,0 +1,1062 @@
+package hbc
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/png"
+	"io"
+	"io/fs"
+	"log"
+	"math"
+	"math/rand"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	screenWidth  = 810
+	screenHeight = 456
+	sampleRate   = 44100
+	loopStart    = 6 * 60
+	loopEnd      = 22 * 60
+	startBoom    = 248
+
+	shutdownFadeFrames = 30
+	quitConfirmFrames  = 3 * 60
+	flashFrames        = 12
+	flashFadeFrames    = 10
+	burstBubbles       = 24
+)
+
+// Intro is the Homebrew Channel intro animation with its music. It
+// implements ebiten.Game, so it can be run directly once its assets have
+// loaded, or through Main like the go-hbc-intro command does.
+type Intro struct {
+	count        int
+	cfg          Config
+	seed         int64
+	rng          *rand.Rand
+	assets       fs.FS
+	assetsSource string
+	strict       bool
+	textures     map[string]*ebiten.Image
+	// textureDensity is the pixels per scene pixel of textures rasterized
+	// from SVG; drawImage scales them back down.
+	textureDensity map[*ebiten.Image]float64
+	bubbleTypes    []BubbleType
+	waveElements   []Element
+	customElements []Element
+	bubbles        []Bubble
+	liveBubbles    []Bubble
+	audioContext   *audio.Context
+	introPlayer    *audio.Player
+	loopPlayer     *audio.Player
+	introPlayed    bool
+	debugMode      bool
+
+	// The music's streams are kept to recreate the players on another
+	// output device.
+	introStream audioStream
+	loopStream  audioStream
+	audioWatch  audioWatch
+	playback    playbackClock
+	timeline    timeline
+	layout      *bubbleLayout
+
+	quitRequested atomic.Bool
+	shuttingDown  bool
+	shutdownFrame int
+	confirmQuit   bool
+	quitPrompt    int
+
+	input               *Input
+	paused              bool
+	muted               bool
+	screenshotRequested bool
+	screenshotClipboard bool
+	helpTimer           int
+	helpPinned          bool
+	frameTimes          frameTimes
+	memStats            memStats
+	memStatsTick        int
+	introFrames         int
+	silent              bool
+	offline             bool
+	// kiosk ignores visitors: no easter egg and nothing follows the pointer
+	// or the keyboard. runScene then restarts the intro on errors.
+	kiosk bool
+
+	ticks          int
+	loopCount      int
+	presence       chan presence
+	chat           chan string
+	chatCfg        TwitchConfig
+	chatTokens     float64
+	chatBubbleType int
+	chatLabelColor color.RGBA
+
+	triggers    chan Action
+	flashFrames int
+	themeIndex  int
+	palette     paletteState
+	pointer     pointer
+	wiiload     *wiiloadTransfer
+	sdIcon      *sdIcon
+	weather     *weatherState
+	titleMotion titleMotionState
+	frameLimit  frameLimiter
+	windowIcon  image.Image
+	taskbar     taskbarProgress
+	mini        miniMode
+	viewport    viewportRect
+	wall        wallSync
+
+	layers       []drawLayer
+	events       eventBus
+	started      bool
+	resumed      bool
+	persistState bool
+
+	volume       float64
+	controls     chan controlCommand
+	controlState chan string
+	controlTick  int
+
+	screenFilter  bool
+	reducedMotion bool
+	audioOffset   time.Duration
+	mixer         mixer
+	clockStart    time.Time
+	clockTicks    int
+	blubTick      int
+	introStarted  bool
+	settingsOpen  bool
+	settingsRow   int
+	loaded        bool
+	effects       []Effect
+	// failedEffects is parallel to effects, marking those whose Init failed.
+	failedEffects []bool
+
+	ticker    *ticker
+	clock     clock
+	watermark *watermark
+
+	mic        *micLevel
+	micCfg     MicConfig
+	micBubbles float64
+
+	bubbleDensity float64
+	// bubbleShare is the part of the scripted bubbles drawn, lowered by
+	// updateAdaptive when frames are missed.
+	bubbleShare float64
+	// bubbleSprites is the draw buffer simulateBubbles fills each frame.
+	bubbleSprites []bubbleSprite
+	renderScale   float64
+	view          ebiten.GeoM
+	width, height float64
+	ui            *ebiten.Image
+	seamCanvas    *ebiten.Image
+	rippleCanvas  *ebiten.Image
+	composeCanvas *ebiten.Image
+	embedLoader   *assetLoader
+	layerBlends   map[Layer]ebiten.Blend
+	blend         ebiten.Blend
+	// vectorRenderer draws the waves, fade and bubbles as paths.
+	vectorRenderer bool
+
+	creditsRequested bool
+	creditsRolling   bool
+	appsRequested    bool
+	appGridOpen      bool
+
+	party    partyMode
+	game     minigame
+	ripples  []ripple
+	parallax parallax
+	// windowFit is the canvas size and pixel ratio fitToWindow last
+	// adapted to.
+	windowFit [3]float64
+	lowMemory bool
+	noShaders bool
+
+	lazyTextures bool
+	// textureUse is the tick each texture was last drawn in.
+	textureUse map[string]int
+}
+
+type BubbleType struct {
+	name   string
+	width  float64
+	height float64
+	chance float64
+}
+
+type Bubble struct {
+	typeID   int
+	x        float64
+	y        float64
+	startX   float64
+	startY   float64
+	endY     float64
+	alpha    float64
+	scale    float64
+	rotation float64
+	start    int
+	end      int
+	length   int
+	label    string
+	// popped is one more than the loop in which the bubble was popped by
+	// clicking it, so that it only stays away for that loop.
+	popped int
+}
+
+type Element struct {
+	name string
+	// x is the offset of the element's centre from the middle of the
+	// screen, and y its offset below the water surface.
+	x, y       float64
+	width      float64
+	height     float64
+	rotation   float64
+	loop       bool
+	scale      float64
+	animateX   bool
+	animateY   bool
+	animSpeedX float64
+	animSpeedY float64
+	animRangeX float64
+	animRangeY float64
+	// depth places the element for the tilt parallax, from 0 at the back
+	// to 1 at the front.
+	depth float64
+}
+
+func loadImage(fsys fs.FS, path string) (io.Reader, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+func loadWav(fsys fs.FS, path string) (io.Reader, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// New creates an intro from cfg that loads its textures and music from
+// assets, laid out like DefaultAssets.
+func New(cfg Config, assets fs.FS) *Intro {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	g := &Intro{
+		cfg:            cfg,
+		seed:           seed,
+		rng:            rand.New(rand.NewSource(seed)),
+		assets:         assets,
+		input:          NewInput(cfg.Keys),
+		textures:       make(map[string]*ebiten.Image),
+		textureDensity: make(map[*ebiten.Image]float64),
+		textureUse:     make(map[string]int),
+		debugMode:      true,
+		introPlayed:    false,
+		helpTimer:      helpStartupFrames,
+		triggers:       make(chan Action, 16),
+		volume:         1,
+	}
+	g.reducedMotion = cfg.ReducedMotion
+	g.timeline = cfg.Timeline.resolve()
+	if cfg.NoAudio {
+		g.silent = true
+	} else {
+		if cfg.AudioDevice != "" {
+			if err := selectAudioDevice(cfg.AudioDevice); err != nil {
+				log.Printf("Warning: Could not select audio device %q: %v\n", cfg.AudioDevice, err)
+			}
+		}
+		g.audioContext = audio.NewContext(sampleRate)
+	}
+	g.setupLayout(cfg.Aspect)
+	g.setupViewport(cfg.Wall.Viewport)
+	g.applyProfile(cfg.Profile)
+	g.setRenderer(cfg.Renderer)
+	g.setTheme(cfg.Theme)
+	g.setupTitleMotion()
+	g.setupWatermark()
+	g.setupLayers()
+	g.setupBlends()
+	g.setupEffects()
+	g.setupBackdrop(cfg.Backdrop)
+	g.setupBubbleTypes()
+	g.setupProceduralBubbleTypes()
+	g.setupBubbleLayout(cfg.Bubbles.Layout)
+	g.setupSpawns()
+	g.setupWaveElements()
+	g.setupElements()
+	g.generateBubbles()
+	g.setupCredits()
+	g.setupApps()
+	g.setupMinigame()
+	g.setupAdaptive()
+	g.SetFrameLimit(cfg.FrameLimit)
+	startTiltSensor()
+
+	return g
+}
+
+// finishLoading turns the decoded assets into textures and audio players.
+// It has to run on the game loop.
+func (g *Intro) finishLoading(l *assetLoader) {
+	g.loadTextures(l)
+
+	g.initAudio(l.audio["intro"], l.audio["loop"])
+	g.setVolume(g.baseVolume())
+	if g.resumed {
+		g.seekAudio()
+	}
+	g.loaded = true
+	g.initEffects()
+}
+
+func (g *Intro) initAudio(introDec, loopDec audioStream) {
+	g.introStream, g.loopStream = introDec, loopDec
+	if introDec != nil {
+		g.introFrames = int(introDec.Length() * 60 / (sampleRate * 4))
+	}
+	// Without an audio context the streams only give the jingle's length.
+	if g.audioContext == nil {
+		return
+	}
+	var err error
+	if introDec != nil {
+		g.introPlayer, err = g.audioContext.NewPlayer(introDec)
+		if err != nil {
+			log.Printf("Warning: Could not create intro player: %v\n", err)
+		} else {
+			g.setBufferSize(g.introPlayer)
+		}
+	}
+
+	if loopDec != nil {
+		loopLoop := audio.NewInfiniteLoop(loopDec, loopDec.Length())
+		if l, ok := loopDec.(loopedStream); ok {
+			start, length := l.Loop()
+			loopLoop = audio.NewInfiniteLoopWithIntro(loopDec, start, length)
+		}
+		g.loopPlayer, err = g.audioContext.NewPlayer(loopLoop)
+		if err != nil {
+			log.Printf("Warning: Could not create loop player: %v\n", err)
+		} else {
+			g.setBufferSize(g.loopPlayer)
+		}
+	}
+}
+
+// setBufferSize applies the configured buffer size to p. Ebiten only lets it
+// be set per player, not on the audio context.
+func (g *Intro) setBufferSize(p *audio.Player) {
+	if g.cfg.AudioBuffer > 0 {
+		p.SetBufferSize(time.Duration(g.cfg.AudioBuffer) * time.Millisecond)
+	}
+}
+
+// introPlaying reports whether the intro jingle is still running. Offline
+// renders have no audio clock, so they derive it from the jingle's length.
+func (g *Intro) introPlaying() bool {
+	if g.offline {
+		return g.count < g.introFrames
+	}
+	return g.introPlayer != nil && g.introPlayer.IsPlaying()
+}
+
+func (g *Intro) baseVolume() float64 {
+	if g.muted {
+		return 0
+	}
+	return g.volume
+}
+
+func (g *Intro) setVolume(volume float64) {
+	music := volume * (1 - g.mixer.duck)
+	if g.introPlayer != nil {
+		g.introPlayer.SetVolume(music)
+	}
+	if g.loopPlayer != nil {
+		g.loopPlayer.SetVolume(music)
+	}
+	if g.party.player != nil {
+		g.party.player.SetVolume(music)
+	}
+	g.setAmbienceVolume(volume)
+}
+
+func (g *Intro) closeAudio() {
+	g.stopAmbience()
+	for _, p := range []*audio.Player{g.introPlayer, g.loopPlayer, g.party.player} {
+		if p == nil {
+			continue
+		}
+		p.Pause()
+		if err := p.Close(); err != nil {
+			log.Printf("Warning: Could not close audio player: %v\n", err)
+		}
+	}
+}
+
+func (g *Intro) updateQuitKey() {
+	if g.quitPrompt > 0 {
+		g.quitPrompt--
+	}
+	if !g.input.JustPressed(ActionQuit) {
+		return
+	}
+	if g.confirmQuit && g.quitPrompt == 0 {
+		g.quitPrompt = quitConfirmFrames
+		return
+	}
+	g.shuttingDown = true
+}
+
+func (g *Intro) drawQuitPrompt(screen *ebiten.Image) {
+	if g.quitPrompt == 0 || g.shuttingDown {
+		return
+	}
+	msg := "Press Esc again to quit"
+	ebitenutil.DebugPrintAt(screen, msg, int(g.width)/2-len(msg)*3, int(g.height)-24)
+}
+
+// updateShutdown ramps the music down over shutdownFadeFrames and then
+// releases the players so the process exits without an audible click.
+func (g *Intro) updateShutdown() error {
+	g.shutdownFrame++
+	if volume := g.shutdownLevel(); volume > 0 {
+		g.setVolume(volume * g.baseVolume())
+		return nil
+	}
+
+	g.closeAudio()
+	if g.persistState {
+		if err := g.saveState(); err != nil {
+			log.Printf("Warning: Could not save playback state: %v\n", err)
+		}
+		if err := g.savePreferences(); err != nil {
+			log.Printf("Warning: Could not save preferences: %v\n", err)
+		}
+	}
+	return ebiten.Termination
+}
+
+// shutdownLevel is how loud the quit fade has left the audio, from 1 before
+// it starts to 0 at its end. Anything else that sets a volume during the
+// fade scales it by this, so as not to bring the sound back up.
+func (g *Intro) shutdownLevel() float64 {
+	if !g.shuttingDown {
+		return 1
+	}
+	return max(1-float64(g.shutdownFrame)/shutdownFadeFrames, 0)
+}
+
+func (g *Intro) setupBubbleTypes() {
+	g.bubbleTypes = []BubbleType{
+		{name: "abubble1.png", width: 48, height: 48, chance: 1},
+		{name: "abubble2.png", width: 32, height: 32, chance: 1},
+		{name: "abubble3.png", width: 16, height: 16, chance: 1},
+		{name: "abubble4.png", width: 24, height: 24, chance: 1},
+		{name: "abubble5.png", width: 32, height: 32, chance: 1},
+		{name: "abubble6.png", width: 16, height: 16, chance: 1},
+		{name: "bbubble1.png", width: 48, height: 48, chance: 1},
+		{name: "cbubble1.png", width: 64, height: 64, chance: 1},
+		{name: "cbubble2.png", width: 16, height: 16, chance: 1},
+	}
+}
+
+func (g *Intro) setupWaveElements() {
+	aniSpeedX := 1.0
+	g.waveElements = []Element{
+		{
+			name:       "banner_wavea.png",
+			x:          -100,
+			y:          10,
+			width:      1024,
+			height:     32,
+			animateX:   true,
+			animateY:   true,
+			animRangeX: 200,
+			animRangeY: 5,
+			animSpeedX: aniSpeedX,
+			animSpeedY: 6,
+			loop:       true,
+			depth:      0.2,
+		},
+		{
+			name:       "banner_waveb.png",
+			x:          -100,
+			y:          15,
+			width:      1024,
+			height:     32,
+			animateX:   true,
+			animateY:   true,
+			animRangeX: 200,
+			animRangeY: 5,
+			animSpeedX: aniSpeedX * 2.0,
+			animSpeedY: 8,
+			loop:       true,
+			depth:      0.4,
+		},
+		{
+			name:       "banner_wave1a.png",
+			x:          -200,
+			y:          40,
+			width:      382,
+			height:     32,
+			animateX:   true,
+			animateY:   true,
+			animRangeX: 400,
+			animRangeY: 20,
+			animSpeedX: aniSpeedX * 2.0,
+			animSpeedY: 6 * 0.2,
+			depth:      0.7,
+		},
+		{
+			name:       "banner_wave1b.png",
+			x:          200,
+			y:          50,
+			width:      527,
+			height:     37,
+			animateX:   true,
+			animateY:   true,
+			animRangeX: 200,
+			animRangeY: 13,
+			animSpeedX: aniSpeedX * 2.2,
+			animSpeedY: 6 * 0.2,
+			depth:      0.8,
+		},
+		{
+			name:       "banner_wave1b.png",
+			x:          -400,
+			y:          45,
+			width:      527,
+			height:     37,
+			animateX:   true,
+			animateY:   true,
+			animRangeX: 200,
+			animRangeY: 20,
+			animSpeedX: aniSpeedX * 2.7,
+			animSpeedY: 6 * 0.2,
+			depth:      0.9,
+		},
+		{
+			name:       "banner_shape2.png",
+			x:          -180,
+			y:          50,
+			width:      644,
+			height:     28,
+			animateX:   true,
+			animateY:   true,
+			animRangeX: 280,
+			animRangeY: 5,
+			animSpeedX: aniSpeedX * 1.4,
+			animSpeedY: 6 * 0.2,
+			depth:      1,
+		},
+	}
+}
+
+func (g *Intro) chooseBubbleType() int {
+	var sumChances float64
+	for _, bt := range g.bubbleTypes {
+		sumChances += bt.chance
+	}
+
+	opt := g.rng.Float64() * sumChances
+	for i, bt := range g.bubbleTypes {
+		if bt.chance > opt {
+			return i
+		}
+		opt -= bt.chance
+	}
+
+	return len(g.bubbleTypes) - 1
+}
+
+func (g *Intro) generateBubbles() {
+	g.bubbles = []Bubble{}
+	if g.layout != nil {
+		g.bubbles = g.layoutBubbles()
+		g.wrapBubbles()
+		return
+	}
+
+	bubbleBoom := g.timeline.bubbles
+
+	// Larger layouts get more bubbles, so they are as dense as in 810x456.
+	density := g.bubbleDensity * g.width * g.height / (screenWidth * screenHeight)
+
+	for i := 0; i < int(100*density); i++ {
+		g.addBubble(bubbleBoom)
+	}
+
+	for i := 0; i < int(280*density); i++ {
+		start := int(g.rng.Float64()*float64(loopEnd-bubbleBoom)) + bubbleBoom
+		g.addBubble(start)
+	}
+	g.addScriptedBubbles()
+
+	filteredBubbles := []Bubble{}
+	for _, b := range g.bubbles {
+		if b.end <= loopEnd {
+			filteredBubbles = append(filteredBubbles, b)
+		}
+	}
+	g.bubbles = filteredBubbles
+	g.wrapBubbles()
+}
+
+// regenerateBubbles generates the bubbles afresh from the intro's seed and
+// returns a copy of them.
+func (g *Intro) regenerateBubbles() []Bubble {
+	g.rng = rand.New(rand.NewSource(g.seed))
+	g.generateBubbles()
+	return slices.Clone(g.bubbles)
+}
+
+// wrapBubbles adds copies of the bubbles still rising when the loop starts,
+// so they carry on across the seam.
+func (g *Intro) wrapBubbles() {
+	extraBubbles := []Bubble{}
+	for _, b := range g.bubbles {
+		if b.start < loopStart && b.end > loopStart {
+			new := b
+			new.start = new.start - loopStart + loopEnd
+			new.end = new.end - loopStart + loopEnd
+			extraBubbles = append(extraBubbles, new)
+		}
+	}
+	g.bubbles = append(g.bubbles, extraBubbles...)
+}
+
+func (g *Intro) addBubble(start int) {
+	g.bubbles = append(g.bubbles, g.newBubble(start))
+}
+
+func (g *Intro) newBubble(start int) Bubble {
+	x := g.rng.Float64()*(g.width+128) - 64 - g.width/2
+	length := g.rng.Float64()*180 + 50
+
+	// Bubbles start well below the bottom edge, however tall the layout is.
+	yStart := screenWidth + g.height - screenHeight
+	yEnd := 170.0
+
+	return Bubble{
+		typeID:   g.chooseBubbleType(),
+		x:        x,
+		y:        yStart,
+		startX:   x,
+		startY:   yStart,
+		endY:     yEnd,
+		alpha:    0,
+		scale:    1.0,
+		rotation: g.rng.Float64() * math.Pi * 2,
+		start:    start,
+		end:      start + int(length),
+		length:   int(length),
+	}
+}
+
+// spawnBubble adds a bubble that starts rising now, of a random type if
+// typeID is negative. Unlike the generated ones it is timed by g.ticks, so it
+// survives the loop wrapping around.
+func (g *Intro) spawnBubble(typeID int, label string) {
+	b := g.newBubble(g.ticks)
+	if typeID >= 0 {
+		b.typeID = typeID
+	}
+	b.label = label
+	g.liveBubbles = append(g.liveBubbles, b)
+
+	// A burst spawns many bubbles in the same tick; one sound is enough.
+	if g.blubTick != g.ticks {
+		g.blubTick = g.ticks
+		g.playSound(blubSound, g.bubblePan(b))
+	}
+}
+
+func (g *Intro) pruneLiveBubbles() {
+	live := g.liveBubbles[:0]
+	for _, b := range g.liveBubbles {
+		if g.ticks < b.end {
+			live = append(live, b)
+		} else {
+			g.playSound(popSound, g.bubblePan(b))
+		}
+	}
+	g.liveBubbles = live
+}
+
+func (g *Intro) Draw(screen *ebiten.Image) {
+	g.frameTimes.tick(time.Now())
+	g.drawLayers(screen)
+}
+
+func (g *Intro) drawWaves(screen *ebiten.Image) {
+	for _, elem := range g.waveElements {
+		g.drawElement(screen, elem)
+	}
+}
+
+// drawElement draws a wave layer or custom element where its motion puts it
+// at the current frame.
+func (g *Intro) drawElement(screen *ebiten.Image, elem Element) {
+	frame := g.count
+	startX, startY := elem.x, g.waterLevel()+elem.y
+	x, y := startX, startY
+
+	if elem.animateX {
+		progress := math.Sin(float64(frame)/60.0*elem.animSpeedX)*0.5 + 0.5
+		x = startX + progress*elem.animRangeX*g.motionScale()
+	}
+
+	if elem.animateY {
+		progress := math.Sin(float64(frame)/60.0*elem.animSpeedY)*0.5 + 0.5
+		y = startY + progress*elem.animRangeY*g.waveAmplitude()*g.motionScale()
+	}
+	px, py := g.parallaxOffset(elem.depth)
+	x, y = x+px, y+py
+
+	op := &ebiten.DrawImageOptions{}
+	w, h := elem.width, elem.height
+	op.GeoM.Translate(-w/2, -h/2)
+
+	if elem.rotation != 0 {
+		op.GeoM.Rotate(elem.rotation)
+	}
+	if elem.scale != 0 && elem.scale != 1 {
+		op.GeoM.Scale(elem.scale, elem.scale)
+	}
+	op.GeoM.Translate(g.width/2+x, y)
+	g.tint(op)
+
+	if !elem.loop {
+		g.drawWave(screen, elem.name, op)
+		return
+	}
+	// Looping waves repeat sideways so they cover layouts wider than
+	// the texture.
+	span := elem.width
+	if elem.scale != 0 {
+		span *= elem.scale
+	}
+	left := g.width/2 + x - span/2
+	first := -math.Ceil(left / span)
+	for k := first; left+k*span < g.width; k++ {
+		tile := *op
+		tile.GeoM.Translate(k*span, 0)
+		g.drawWave(screen, elem.name, &tile)
+	}
+}
+
+func (g *Intro) drawBubbles(screen *ebiten.Image) {
+	frame := g.count
+
+	for i, s := range g.simulateBubbles(frame) {
+		g.drawBubbleSprite(screen, g.bubbles[i], s)
+	}
+	for _, bubble := range g.liveBubbles {
+		g.drawBubbleSprite(screen, bubble, g.bubbleSprite(bubble, g.ticks))
+	}
+}
+
+// bubblePosition returns where bubble is at frame, relative to the middle of
+// the screen at the top, and how far along its rise it is. ok is false while
+// it is not on screen.
+func bubblePosition(bubble Bubble, frame int) (x, y, progress float64, ok bool) {
+	if frame < bubble.start || frame >= bubble.end {
+		return 0, 0, 0, false
+	}
+	progress = float64(frame-bubble.start) / float64(bubble.length)
+	return bubble.startX, bubble.startY + (bubble.endY-bubble.startY)*progress, progress, true
+}
+
+func (g *Intro) drawBubbleSprite(screen *ebiten.Image, bubble Bubble, s bubbleSprite) {
+	if !s.visible {
+		return
+	}
+
+	bubbleType := g.bubbleTypes[bubble.typeID]
+
+	op := &ebiten.DrawImageOptions{}
+	w, h := bubbleType.width, bubbleType.height
+	op.GeoM.Translate(-w/2, -h/2)
+	op.GeoM.Rotate(s.rotation)
+	op.GeoM.Translate(s.x, s.y)
+	op.ColorScale.ScaleAlpha(float32(s.alpha))
+	op.Filter = ebiten.FilterLinear
+	g.tint(op)
+
+	if g.vectorRenderer {
+		g.drawVectorBubble(screen, w, op)
+	} else {
+		g.drawImage(screen, g.texture(bubbleType.name), op)
+	}
+
+	if bubble.label != "" {
+		g.drawBubbleLabel(screen, bubble, s.x, s.y+h/2+4, s.alpha)
+	}
+}
+
+func (g *Intro) drawTitle(screen *ebiten.Image) {
+	frame := g.count
+	titleImg := g.texture("banner_title.png")
+	width := 400.0
+	height := 180.0
+
+	y := 32.0
+	var pose titlePose
+	if frame >= g.timeline.title {
+		pose = g.titlePose(float64(frame))
+		y = 22.0 + pose.y
+	}
+
+	alpha := 0.0
+	if frame >= g.timeline.title {
+		alpha = 1.0
+	} else if frame >= g.timeline.title-1 {
+		alpha = float64(frame - g.timeline.title - 1)
+	}
+
+	op := &ebiten.DrawImageOptions{}
+
+	// The title rocks about its middle.
+	op.GeoM.Translate(-width/2, -height/2)
+	op.GeoM.Rotate(pose.angle)
+	op.GeoM.Translate(pose.x, height)
+	// The title keeps its distance from the top, so it stays above the water
+	// in portrait layouts too.
+	op.GeoM.Translate(g.width/2, screenHeight/4+y)
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	g.tint(op)
+
+	g.drawImage(screen, titleImg, op)
+}
+
+func (g *Intro) drawFade(screen *ebiten.Image) {
+	fadeImg := g.texture("banner_fade.png")
+	width := g.width
+	// The gradient reaches the bottom, so taller layouts get a taller
+	// water column.
+	height := g.height - 200
+
+	op1 := &ebiten.DrawImageOptions{}
+	fadeW, fadeH := 1.0, 1.0
+	if !g.vectorRenderer {
+		fadeW, fadeH = g.textureSize(fadeImg)
+	}
+	op1.GeoM.Scale(width/fadeW, height/fadeH)
+
+	aniProgress := g.timeline.riseProgress(g.count)
+	initialY := 200
+	targetSize := (float64(initialY)-g.height)*aniProgress + g.height
+	op1.GeoM.Translate(0, targetSize)
+
+	op1.Filter = ebiten.FilterLinear
+	g.tint(op1)
+	if g.vectorRenderer {
+		g.drawVectorFade(screen, op1)
+		return
+	}
+	g.drawImage(screen, fadeImg, op1)
+}
+
+func (g *Intro) drawBoom(screen *ebiten.Image) {
+	frame := g.count
+	t := g.timeline
+
+	if !g.introPlaying() && frame < t.titleLanded() {
+		alpha := 0.0
+
+		if frame <= t.flash {
+			alpha = 1.0
+		} else {
+			alpha = 1.0 - float64(frame-t.flash)/float64(t.flashFade)
+		}
+
+		op := &ebiten.DrawImageOptions{}
+		op.ColorScale.ScaleAlpha(float32(alpha) * g.flashStrength())
+
+		whiteImg := g.texture("white.png")
+
+		op.GeoM.Scale(g.width, g.height)
+		g.drawImage(screen, whiteImg, op)
+	}
+
+	if g.flashFrames > 0 {
+		op := &ebiten.DrawImageOptions{}
+		op.ColorScale.ScaleAlpha(float32(g.flashFrames) / flashFrames * g.flashStrength())
+		op.GeoM.Scale(g.width, g.height)
+		g.drawImage(screen, g.texture("white.png"), op)
+	}
+}
+
+func (g *Intro) Update() error {
+	g.updateQuitKey()
+	if g.quitRequested.Load() || ebiten.IsWindowBeingClosed() {
+		g.shuttingDown = true
+	}
+	if g.shuttingDown {
+		if err := g.updateShutdown(); err != nil {
+			return err
+		}
+	}
+
+	// An embedded intro waits for DrawTo to finish loading.
+	if !g.loaded {
+		return nil
+	}
+	defer g.publishPlayback()
+	if !g.started {
+		g.started = true
+		g.emit(EventIntroStarted)
+	}
+	// The Konami code is checked before the actions, which skip the keys
+	// it takes.
+	g.input.newTick()
+	if !g.paused {
+		g.updateKonamiCode()
+	}
+	g.updateActions()
+	g.updateControls()
+	g.updateWall()
+	g.updatePalette()
+	g.updatePointer()
+	g.updateWiiload()
+	g.updateWeather()
+	if g.paused && !g.shuttingDown {
+		return nil
+	}
+
+	from, loop := g.count, g.loopCount
+	g.catchUp()
+	g.updateParty()
+	g.updateMinigame()
+	g.updateRipples()
+	g.updateParallax()
+	g.sweepTextures()
+	g.updateAdaptive()
+	g.count++
+	g.ticks++
+	g.pruneLiveBubbles()
+	g.updateChat()
+	g.updateMic()
+	g.updateTicker()
+	g.updateTaskbarProgress()
+	g.updateRain()
+	if err := g.updateEffects(); err != nil {
+		return err
+	}
+
+	g.updatePlayers()
+	g.watchAudio()
+	g.updateAmbience()
+	g.updateDucking()
+
+	if g.count >= loopEnd {
+		g.count -= loopEnd - loopStart
+		g.loopCount++
+		g.emit(EventLoopWrapped)
+	}
+	g.emitMilestones(from, g.loopCount > loop)
+
+	return nil
+}
+
+func (g *Intro) updateActions() {
+	g.updateHelp()
+	g.updateSettings()
+	if g.debugMode {
+		g.sampleMemStats()
+	}
+	if g.flashFrames > 0 {
+		g.flashFrames--
+	}
+
+	for _, a := range []Action{ActionDebug, ActionPause, ActionMute, ActionScreenshot, ActionFullscreen, ActionBurst, ActionFlash, ActionTheme, ActionSettings, ActionScaleUp, ActionScaleDown, ActionCredits, ActionApps, ActionMini} {
+		if g.input.JustPressed(a) {
+			g.perform(a)
+		}
+	}
+	for len(g.triggers) > 0 {
+		g.perform(<-g.triggers)
+	}
+}
+
+// perform carries out an action, whether it came from the keyboard or from
+// an external trigger such as MIDI.
+func (g *Intro) perform(a Action) {
+	switch a {
+	case ActionDebug:
+		g.debugMode = !g.debugMode
+	case ActionPause:
+		g.setPaused(!g.paused)
+	case ActionMute:
+		g.muted = !g.muted
+		g.setVolume(g.baseVolume())
+	case ActionScreenshot:
+		g.screenshotRequested = true
+		g.screenshotClipboard = ebiten.IsKeyPressed(ebiten.KeyControl)
+	case ActionFullscreen:
+		ebiten.SetFullscreen(!ebiten.IsFullscreen())
+	case ActionBurst:
+		for i := 0; i < burstBubbles; i++ {
+			g.spawnBubble(-1, "")
+		}
+		g.emit(EventBubbleBurst)
+	case ActionFlash:
+		g.flashFrames = flashFrames
+	case ActionTheme:
+		g.cycleTheme()
+	case ActionSettings:
+		g.settingsOpen = !g.settingsOpen
+	case ActionScaleUp:
+		g.stepRenderScale(1)
+	case ActionScaleDown:
+		g.stepRenderScale(-1)
+	case ActionCredits:
+		g.creditsRequested = true
+	case ActionApps:
+		g.appsRequested = true
+	case ActionMini:
+		g.mini.requested = true
+	case ActionQuit:
+		g.shuttingDown = true
+	}
+}
+
+// setPaused freezes the frame counter and the music. Update restarts the
+// players by itself once the game is unpaused.
+func (g *Intro) setPaused(paused bool) {
+	g.paused = paused
+	g.resetClock()
+	if !paused {
+		g.emit(EventResumed)
+		return
+	}
+	g.emit(EventPaused)
+	for _, p := range []*audio.Player{g.introPlayer, g.loopPlayer, g.mixer.ambience, g.party.player} {
+		if p != nil {
+			p.Pause()
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package hbc
+
+import "testing"
+
+// newTestIntro creates an intro from the embedded assets that needs no
+// window or sound device, and runs on the animation's own clock.
+func newTestIntro(t testing.TB, seed int64) *Intro {
+	t.Helper()
+	g := New(Config{Seed: seed, NoAudio: true}, DefaultAssets())
+	g.prepareOffline()
+	return g
+}
@@ -0,0 +1,83 @@
+package hbc
+
+import (
+	"syscall/js"
+)
+
+// startJSAPI publishes window.hbcIntro so that the page around the canvas
+// can drive the intro:
+//
+//	hbcIntro.play()          hbcIntro.pause()
+//	hbcIntro.seek(seconds)   hbcIntro.setVolume(0.5)
+//	hbcIntro.setFrameLimit(30)  draws at most 30 frames a second, 0 for no cap
+//	hbcIntro.onLoop(fn)      fn(loop) runs each time the loop wraps around
+//	hbcIntro.state()         {frame, loop, elapsed, phase, paused, volume}
+//
+// Commands go through the same queue as the control window's, so they take
+// effect on the next tick.
+func (g *Intro) startJSAPI() {
+	if g.controls == nil {
+		g.controls = make(chan controlCommand, 16)
+	}
+	send := func(op string, value float64) {
+		select {
+		case g.controls <- controlCommand{op: op, value: value}:
+		default:
+		}
+	}
+	arg := func(args []js.Value) float64 {
+		if len(args) == 0 || args[0].Type() != js.TypeNumber {
+			return 0
+		}
+		return args[0].Float()
+	}
+
+	var loopCallbacks []js.Value
+	g.Subscribe(EventLoopWrapped, func(e Event) {
+		for _, fn := range loopCallbacks {
+			fn.Invoke(e.Loop)
+		}
+	})
+
+	api := map[string]any{
+		"play": js.FuncOf(func(this js.Value, args []js.Value) any {
+			send("play", 0)
+			return nil
+		}),
+		"pause": js.FuncOf(func(this js.Value, args []js.Value) any {
+			send("pause", 0)
+			return nil
+		}),
+		"seek": js.FuncOf(func(this js.Value, args []js.Value) any {
+			send("seek", arg(args)*60)
+			return nil
+		}),
+		"setVolume": js.FuncOf(func(this js.Value, args []js.Value) any {
+			send("volume", arg(args))
+			return nil
+		}),
+		"setFrameLimit": js.FuncOf(func(this js.Value, args []js.Value) any {
+			send("frame-limit", arg(args))
+			return nil
+		}),
+		"onLoop": js.FuncOf(func(this js.Value, args []js.Value) any {
+			if len(args) > 0 && args[0].Type() == js.TypeFunction {
+				loopCallbacks = append(loopCallbacks, args[0])
+			}
+			return nil
+		}),
+		// state is read while the game loop may be running; it is only a
+		// snapshot for display. elapsed is in seconds.
+		"state": js.FuncOf(func(this js.Value, args []js.Value) any {
+			return map[string]any{
+				"frame":   g.CurrentFrame(),
+				"loop":    g.LoopCount(),
+				"elapsed": g.Elapsed().Seconds(),
+				"phase":   g.Phase().String(),
+				"paused":  g.paused,
+				"volume":  g.volume,
+			}
+		}),
+	}
+	js.Global().Set("hbcIntro", api)
+}
@@ -0,0 +1,6 @@
+//go:build !js
+
+package hbc
+
+// startJSAPI only exists in the browser.
+func (g *Intro) startJSAPI() {}
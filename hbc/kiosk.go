@@ -0,0 +1,180 @@
+package hbc
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// KioskConfig runs the intro unattended for days, as in a museum or at an
+// exhibit.
+type KioskConfig struct {
+	// Enabled starts fullscreen with the cursor hidden and the keyboard
+	// ignored. Holding Esc for a few seconds still quits.
+	Enabled bool `json:"enabled,omitempty"`
+	// Log is a file that gets a line with the uptime, the loops played and
+	// the number of restarts every LogMinutes, 60 by default.
+	Log        string `json:"log,omitempty"`
+	LogMinutes int    `json:"log_minutes,omitempty"`
+}
+
+const (
+	// kioskQuitFrames is how long Esc has to be held to leave kiosk mode.
+	kioskQuitFrames = 3 * 60
+	// kioskMaxRestarts restarts within kioskRestartWindow mean the intro
+	// cannot recover, so the error is returned for a service manager to
+	// restart the whole process instead.
+	kioskMaxRestarts   = 5
+	kioskRestartWindow = time.Minute
+	// kioskMaxCrashLogs keeps a fault that repeats for days from filling
+	// the disk with crash logs.
+	kioskMaxCrashLogs = 20
+)
+
+// kioskWatchdog restarts the intro from the beginning whenever it panics or
+// fails, instead of exiting. App runs every scene under it, so that the
+// credits and the app grid are covered as well as the intro.
+type kioskWatchdog struct {
+	app     *App
+	started time.Time
+
+	restarts  int
+	crashLogs int
+	// recent holds the times of the restarts within kioskRestartWindow.
+	recent []time.Time
+
+	logPath     string
+	logInterval time.Duration
+	nextLog     time.Time
+}
+
+func newKioskWatchdog(app *App) *kioskWatchdog {
+	g := app.game
+	c := g.cfg.Kiosk
+	interval := time.Duration(c.LogMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	now := time.Now()
+	k := &kioskWatchdog{app: app, started: now, logPath: c.Log, logInterval: interval, nextLog: now}
+
+	g.debugMode = false
+	g.helpTimer = 0
+	// Without bindings no key does anything, so visitors cannot pause,
+	// open the settings or quit. g.kiosk also turns off the Konami code
+	// and everything the mouse does, such as ripples, bubble sounds and
+	// paging through the app grid.
+	g.input = NewInput(nil)
+	ebiten.SetFullscreen(true)
+	ebiten.SetCursorMode(ebiten.CursorModeHidden)
+	return k
+}
+
+// update runs before the scene's Update, whichever scene is current.
+func (k *kioskWatchdog) update() {
+	if inpututil.KeyPressDuration(ebiten.KeyEscape) >= kioskQuitFrames {
+		k.app.game.quitRequested.Store(true)
+	}
+	k.writeLog(time.Now())
+}
+
+// recoverUpdate restarts the intro after a panic in Update. Like
+// recoverCrash it must be deferred directly, and after it, so that it sees
+// the panic first.
+func (k *kioskWatchdog) recoverUpdate(err *error) {
+	if r := recover(); r != nil {
+		*err = k.restart(r, debug.Stack())
+	}
+}
+
+// recoverDraw restarts the intro after a panic in Draw. Draw cannot fail the
+// game, so a restart that gives up only stops the intro on the next Update.
+func (k *kioskWatchdog) recoverDraw() {
+	if r := recover(); r != nil {
+		if err := k.restart(r, debug.Stack()); err != nil {
+			k.app.game.quitRequested.Store(true)
+		}
+	}
+}
+
+// updateFailed restarts the intro after the scene's Update returned err,
+// unless it is quitting.
+func (k *kioskWatchdog) updateFailed(err error) error {
+	if errors.Is(err, ebiten.Termination) {
+		return err
+	}
+	return k.restart(err, nil)
+}
+
+// restart logs what went wrong and plays the intro again from the start,
+// closing the credits or the app grid if they were up. It returns an error
+// once restarting keeps failing, or when the intro has not loaded yet and
+// there is nothing to go back to.
+func (k *kioskWatchdog) restart(cause any, stack []byte) error {
+	now := time.Now()
+	k.restarts++
+	recent := k.recent[:0]
+	for _, t := range k.recent {
+		if now.Sub(t) < kioskRestartWindow {
+			recent = append(recent, t)
+		}
+	}
+	k.recent = append(recent, now)
+
+	log.Printf("Warning: Restarting the intro after an error: %v\n", cause)
+	if stack != nil && k.crashLogs < kioskMaxCrashLogs {
+		k.crashLogs++
+		if path, err := writeCrashLog(k.app.game, cause, stack); err != nil {
+			log.Printf("Could not write crash log: %v\n", err)
+		} else {
+			log.Printf("Crash log written to %s\n", path)
+		}
+	}
+	if len(k.recent) > kioskMaxRestarts {
+		return fmt.Errorf("restarted %d times in %s, last after: %v", len(k.recent), kioskRestartWindow, cause)
+	}
+	if k.app.home == nil {
+		return fmt.Errorf("failed while loading: %v", cause)
+	}
+	k.app.game.restartIntro()
+	k.app.SetScene(k.app.home)
+	return nil
+}
+
+// restartIntro puts the intro back at its first frame with fresh bubbles,
+// keeping the loaded textures and audio.
+func (g *Intro) restartIntro() {
+	g.liveBubbles = nil
+	g.ripples = nil
+	g.flashFrames = 0
+	g.creditsRequested, g.creditsRolling = false, false
+	g.appsRequested, g.appGridOpen = false, false
+	g.regenerateBubbles()
+	if g.paused {
+		g.setPaused(false)
+	}
+	g.seek(0)
+}
+
+// writeLog appends the uptime line when it is due.
+func (k *kioskWatchdog) writeLog(now time.Time) {
+	if k.logPath == "" || now.Before(k.nextLog) {
+		return
+	}
+	k.nextLog = now.Add(k.logInterval)
+
+	f, err := os.OpenFile(k.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Printf("Warning: Could not write kiosk log %s: %v\n", k.logPath, err)
+		return
+	}
+	defer f.Close()
+	uptime := now.Sub(k.started).Round(time.Second)
+	fmt.Fprintf(f, "%s uptime %s, %d loops, %d restarts\n", now.Format(time.RFC3339), uptime, k.app.game.LoopCount(), k.restarts)
+}
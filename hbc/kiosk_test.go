@@ -0,0 +1,35 @@
+package hbc
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// panicScene stands in for a scene with a bug.
+type panicScene struct{}
+
+func (panicScene) Update() error             { panic("scene failed") }
+func (panicScene) Draw(screen *ebiten.Image) {}
+
+// TestKioskRestartsFromAnyScene checks that a panic in a scene other than
+// the intro, such as the credits, still restarts the intro in kiosk mode.
+func TestKioskRestartsFromAnyScene(t *testing.T) {
+	g := newTestIntro(t, 1)
+	g.loaded = true
+	app := &App{game: g, home: g}
+	// Keep the test from writing crash logs.
+	app.kiosk = &kioskWatchdog{app: app, crashLogs: kioskMaxCrashLogs}
+
+	g.count, g.creditsRolling = 500, true
+	app.SetScene(panicScene{})
+	if err := app.Update(); err != nil {
+		t.Fatalf("Update after a panic: %v, want a restart", err)
+	}
+	if app.scene != Scene(g) {
+		t.Errorf("scene %T after the restart, want the intro", app.scene)
+	}
+	if g.count != 0 || g.creditsRolling {
+		t.Errorf("frame %d with the credits rolling %v after the restart, want frame 0 without", g.count, g.creditsRolling)
+	}
+}
@@ -0,0 +1,162 @@
+package hbc
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Layer orders what Draw puts on screen, back to front. Layers up to
+// LayerFlash are the animation itself and are drawn through the render
+// scale; the rest are overlays laid out at 810x456 on the UI image.
+type Layer int
+
+const (
+	LayerBackground Layer = iota
+	LayerWaves
+	LayerBubbles
+	LayerTitle
+	LayerFlash
+	LayerTicker
+	LayerOverlay
+	LayerWatermark
+)
+
+var layerNames = map[Layer]string{
+	LayerBackground: "background",
+	LayerWaves:      "waves",
+	LayerBubbles:    "bubbles",
+	LayerTitle:      "title",
+	LayerFlash:      "flash",
+	LayerTicker:     "ticker",
+	LayerOverlay:    "overlay",
+	LayerWatermark:  "watermark",
+}
+
+func (l Layer) String() string {
+	return layerNames[l]
+}
+
+func layerByName(name string) (Layer, bool) {
+	for l, n := range layerNames {
+		if n == name {
+			return l, true
+		}
+	}
+	return 0, false
+}
+
+func (l Layer) overlay() bool {
+	return l > LayerFlash
+}
+
+type drawLayer struct {
+	layer Layer
+	draw  func(screen *ebiten.Image)
+}
+
+func (g *Intro) setupLayers() {
+	g.layers = nil
+	g.setupVariant(g.cfg.Variant)
+	g.addLayer(LayerWaves, g.drawRain)
+	g.addLayer(LayerTicker, g.drawTicker)
+	g.addLayer(LayerOverlay, g.drawClock)
+	g.addLayer(LayerOverlay, g.drawMinigame)
+	g.addLayer(LayerOverlay, g.drawWiiload)
+	g.addLayer(LayerOverlay, g.drawQuitPrompt)
+	g.addLayer(LayerOverlay, g.drawDebug)
+	g.addLayer(LayerOverlay, g.drawHelp)
+	g.addLayer(LayerOverlay, g.drawSettings)
+	g.setupSDIcon()
+	g.addLayer(LayerWatermark, g.drawWatermark)
+	g.addLayer(LayerWatermark, g.drawTimecode)
+	g.addLayer(LayerWatermark, g.drawPointer)
+}
+
+// addLayer appends draw to the layer. Within a layer, earlier additions are
+// drawn first.
+func (g *Intro) addLayer(layer Layer, draw func(screen *ebiten.Image)) {
+	g.layers = append(g.layers, drawLayer{layer, draw})
+	sort.SliceStable(g.layers, func(i, j int) bool {
+		return g.layers[i].layer < g.layers[j].layer
+	})
+}
+
+func (g *Intro) drawLayers(screen *ebiten.Image) {
+	g.drawWorld(screen)
+	g.drawSeamCrossfade(screen)
+	g.takeScreenshot(screen)
+
+	ui := g.uiLayer(screen)
+	for _, l := range g.layers {
+		if l.layer.overlay() {
+			l.draw(ui)
+		}
+	}
+	g.flushUILayer(screen, ui)
+}
+
+// takeScreenshot saves or copies screen if a screenshot was asked for. It
+// runs before the overlays are drawn, so screenshots are of the animation
+// only.
+func (g *Intro) takeScreenshot(screen *ebiten.Image) {
+	if !g.screenshotRequested {
+		return
+	}
+	g.screenshotRequested = false
+	if g.screenshotClipboard {
+		copyScreenshot(screen)
+	} else {
+		saveScreenshot(screen)
+	}
+}
+
+// drawWorld draws the animation layers for the current frame, each with the
+// blend mode configured for it.
+func (g *Intro) drawWorld(screen *ebiten.Image) {
+	if len(g.ripples) == 0 {
+		g.drawWorldLayers(screen, LayerBackground, LayerFlash)
+		return
+	}
+
+	// The water goes through the ripple shader on the way to the screen.
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	if g.rippleCanvas == nil || g.rippleCanvas.Bounds().Dx() != w || g.rippleCanvas.Bounds().Dy() != h {
+		g.rippleCanvas = ebiten.NewImage(w, h)
+	}
+	g.rippleCanvas.Clear()
+	g.drawWorldLayers(g.rippleCanvas, LayerBackground, LayerBubbles)
+	g.drawRippled(screen, g.rippleCanvas)
+	g.drawWorldLayers(screen, LayerTitle, LayerFlash)
+}
+
+// drawWorldLayers draws the layers from first to last.
+func (g *Intro) drawWorldLayers(screen *ebiten.Image, first, last Layer) {
+	for _, l := range g.layers {
+		if l.layer >= first && l.layer <= last {
+			g.blend = g.layerBlends[l.layer]
+			l.draw(screen)
+		}
+	}
+	g.blend = ebiten.Blend{}
+}
+
+func (g *Intro) drawBackground(screen *ebiten.Image) {
+	screen.Fill(g.currentPalette().Background)
+
+	bgOp := &ebiten.DrawImageOptions{}
+	bgOp.GeoM.Translate(0, 0)
+	bgOp.ColorScale.ScaleWithColor(g.currentPalette().Background)
+	g.drawImage(screen, g.texture("white.png"), bgOp)
+}
+
+func (g *Intro) drawDebug(ui *ebiten.Image) {
+	if !g.debugMode {
+		return
+	}
+	ebitenutil.DebugPrint(ui, fmt.Sprintf("FPS: %0.2f, Frame: %d/%d %s\n%s\n\n%s", ebiten.ActualFPS(), g.count, loopEnd, g.adaptiveStatus(), versionString(), g.input.Help()))
+	g.frameTimes.draw(ui, 8, float32(g.height)-frameGraphHeight-8)
+	ebitenutil.DebugPrintAt(ui, g.memStats.String(), int(g.width)-260, int(g.height)-56)
+}
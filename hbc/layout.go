@@ -0,0 +1,137 @@
+package hbc
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxAspect keeps the layout from growing without bound on odd ratios. A
+// video wall split into viewports may be wider, up to six 16:9 screens.
+const (
+	maxAspect     = 32.0 / 9
+	maxWallAspect = 6 * 16.0 / 9
+)
+
+// setupLayout picks the logical size the scene is composed for. The banner
+// was drawn for 810x456, a bit wider than 16:9; wider ratios keep the height
+// and extend the water sideways instead of letterboxing. Portrait ratios are
+// 456 wide and grow downwards, with the title at the top over a taller water
+// column. Ratios in between are letterboxed as before.
+func (g *Intro) setupLayout(aspect string) {
+	g.width, g.height = screenWidth, screenHeight
+	if aspect == "" {
+		return
+	}
+
+	ratio, err := parseAspect(aspect)
+	if err != nil {
+		log.Printf("Warning: Invalid aspect ratio %q: %v\n", aspect, err)
+		return
+	}
+	limit := maxAspect
+	if g.cfg.Wall.Viewport != "" {
+		limit = maxWallAspect
+	}
+	ratio = min(max(ratio, 1/limit), limit)
+	switch {
+	case ratio > float64(screenWidth)/screenHeight:
+		g.width = math.Round(screenHeight * ratio)
+	case ratio < 1:
+		g.width = screenHeight
+		g.height = math.Round(screenHeight / ratio)
+	}
+}
+
+// parseAspect reads "21:9", "2.35" or "2.35:1".
+func parseAspect(s string) (float64, error) {
+	w, h, found := strings.Cut(s, ":")
+	num, err := strconv.ParseFloat(w, 64)
+	if err != nil {
+		return 0, err
+	}
+	den := 1.0
+	if found {
+		if den, err = strconv.ParseFloat(h, 64); err != nil {
+			return 0, err
+		}
+	}
+	if num <= 0 || den <= 0 {
+		return 0, fmt.Errorf("want a positive ratio")
+	}
+	return num / den, nil
+}
+
+// layoutSize returns the logical size as whole pixels.
+func (g *Intro) layoutSize() (int, int) {
+	return int(g.width), int(g.height)
+}
+
+// viewportRect is the part of the layout this machine draws, in layout
+// coordinates. A zero width means all of it.
+type viewportRect struct {
+	x, y, width, height float64
+}
+
+// setupViewport picks this screen's part of a video wall from spec, as
+// described for WallConfig.Viewport. The edges are rounded to whole pixels
+// so that neighbouring screens meet without a gap. It has to run before
+// setRenderScale, which moves the view to the viewport.
+func (g *Intro) setupViewport(spec string) {
+	g.viewport = viewportRect{}
+	if spec == "" {
+		return
+	}
+	v, err := parseViewport(spec)
+	if err != nil {
+		log.Printf("Warning: Invalid viewport %q: %v\n", spec, err)
+		return
+	}
+	x0, y0 := math.Round(v[0]*g.width), math.Round(v[1]*g.height)
+	x1, y1 := math.Round((v[0]+v[2])*g.width), math.Round((v[1]+v[3])*g.height)
+	g.viewport = viewportRect{x0, y0, x1 - x0, y1 - y0}
+}
+
+// parseViewport reads "x,y,width,height", each a fraction such as "0.5" or
+// "1/3".
+func parseViewport(s string) ([4]float64, error) {
+	var v [4]float64
+	parts := strings.Split(s, ",")
+	if len(parts) != len(v) {
+		return v, fmt.Errorf("want x,y,width,height")
+	}
+	for i, p := range parts {
+		num, den, found := strings.Cut(strings.TrimSpace(p), "/")
+		n, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return v, err
+		}
+		if found {
+			d, err := strconv.ParseFloat(den, 64)
+			if err != nil {
+				return v, err
+			}
+			if d <= 0 {
+				return v, fmt.Errorf("want a positive denominator")
+			}
+			n /= d
+		}
+		v[i] = n
+	}
+	const eps = 1e-9
+	if v[0] < 0 || v[1] < 0 || v[2] <= 0 || v[3] <= 0 || v[0]+v[2] > 1+eps || v[1]+v[3] > 1+eps {
+		return v, fmt.Errorf("want a rectangle within the canvas")
+	}
+	return v, nil
+}
+
+// viewportSize is the size of what this machine draws: the viewport, or the
+// whole layout.
+func (g *Intro) viewportSize() (float64, float64) {
+	if g.viewport.width == 0 {
+		return g.width, g.height
+	}
+	return g.viewport.width, g.viewport.height
+}
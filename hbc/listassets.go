@@ -0,0 +1,136 @@
+package hbc
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+	"github.com/mewkiz/flac"
+	"github.com/srwiley/oksvg"
+)
+
+// listAssets implements the list-assets subcommand.
+func listAssets(args []string) error {
+	fset, common := newFlagSet("list-assets")
+	fset.Parse(args)
+
+	// The intro is set up as for a run, so that the config's bubbles,
+	// elements and window icon are counted, but it plays nothing.
+	common.noAudio = true
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	fsys := g.assets
+
+	refs := make(map[string][]string)
+	addTexture := func(name string, users ...string) {
+		for _, p := range []string{"img/" + name, "img/" + svgPath(name)} {
+			refs[p] = append(refs[p], users...)
+		}
+	}
+	for name, users := range g.textureRefs() {
+		addTexture(name, users...)
+	}
+	icon := g.cfg.WindowIcon
+	if icon == "" {
+		icon = defaultWindowIcon
+	}
+	addTexture(icon, "window icon")
+	for name, base := range audioPaths {
+		p := findAudio(fsys, base)
+		refs[p] = append(refs[p], name+" music")
+	}
+	refs[creditsFile] = []string{"credits"}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tSIZE\tINFO\tUSED BY")
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		used := "unused"
+		if users := refs[p]; len(users) > 0 {
+			used = strings.Join(users, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p, formatSize(info.Size()), describeAsset(fsys, p), used)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func describeAsset(fsys fs.FS, p string) string {
+	f, err := fsys.Open(p)
+	if err != nil {
+		return err.Error()
+	}
+	defer f.Close()
+
+	switch strings.ToLower(path.Ext(p)) {
+	case ".png":
+		cfg, _, err := image.DecodeConfig(f)
+		if err != nil {
+			return "invalid image: " + err.Error()
+		}
+		return fmt.Sprintf("%dx%d", cfg.Width, cfg.Height)
+	case ".svg":
+		icon, err := oksvg.ReadIconStream(f)
+		if err != nil {
+			return "invalid image: " + err.Error()
+		}
+		return fmt.Sprintf("%gx%g vector", icon.ViewBox.W, icon.ViewBox.H)
+	case ".wav":
+		s, err := wav.DecodeF32(f)
+		if err != nil {
+			return "invalid audio: " + err.Error()
+		}
+		samples := s.Length() / 8 // stereo float32
+		return (time.Duration(samples) * time.Second / time.Duration(s.SampleRate())).Round(time.Millisecond).String()
+	case ".flac":
+		s, err := flac.New(f)
+		if err != nil {
+			return "invalid audio: " + err.Error()
+		}
+		if s.Info.SampleRate == 0 {
+			return "invalid audio: no sample rate"
+		}
+		return (time.Duration(s.Info.NSamples) * time.Second / time.Duration(s.Info.SampleRate)).Round(time.Millisecond).String()
+	case ".mod":
+		data, err := io.ReadAll(f)
+		if err == nil {
+			var m *module
+			if m, err = parseModule(data); err == nil {
+				return fmt.Sprintf("%q, %d channels, %d orders", m.title, m.channels, len(m.orders))
+			}
+		}
+		return "invalid module: " + err.Error()
+	case ".xm":
+		return "invalid module: " + errXM.Error()
+	}
+	return ""
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
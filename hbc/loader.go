@@ -0,0 +1,166 @@
+package hbc
+
+import (
+	"fmt"
+	"image"
+	"io/fs"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+var texturePaths = []string{
+	"banner_title.png", "white.png", "banner_wavea.png", "banner_waveb.png",
+	"banner_wave1a.png", "banner_wave1b.png", "banner_shape2.png", "banner_fade.png",
+	"abubble1.png", "abubble2.png", "abubble3.png", "abubble4.png", "abubble5.png",
+	"abubble6.png", "bbubble1.png", "cbubble1.png", "cbubble2.png",
+}
+
+// audioPaths are without an extension; see audioExtensions.
+var audioPaths = map[string]string{
+	"intro": "audio/intro",
+	"loop":  "audio/loop",
+}
+
+// assetLoader reads and decodes every asset on its own goroutine. Only the
+// CPU side happens here; GPU images and audio players are created on the game
+// loop once finished reports true.
+type assetLoader struct {
+	total int
+	done  atomic.Int32
+	wg    sync.WaitGroup
+
+	mu       sync.Mutex
+	textures map[string]image.Image
+	// densities holds the pixels per scene pixel of textures rasterized
+	// from SVG.
+	densities map[string]float64
+	audio     map[string]audioStream
+	failures  []*assetError
+}
+
+// assetError records an asset that could not be used. Path is relative to the
+// root of the asset pack.
+type assetError struct {
+	path string
+	op   string
+	err  error
+}
+
+func (e *assetError) Error() string {
+	return fmt.Sprintf("could not %s %s: %v", e.op, e.path, e.err)
+}
+
+// startAssetLoader loads the given textures and the music from fsys in the
+// background. SVG textures are rasterized at scale.
+func startAssetLoader(fsys fs.FS, textures []string, scale float64) *assetLoader {
+	l := &assetLoader{
+		total:     len(textures) + len(audioPaths),
+		textures:  make(map[string]image.Image),
+		densities: make(map[string]float64),
+		audio:     make(map[string]audioStream),
+	}
+
+	l.wg.Add(l.total)
+	for _, path := range textures {
+		go func() {
+			defer l.wg.Done()
+			defer l.done.Add(1)
+			img, density, err := decodeTexture(fsys, path, scale)
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if err != nil {
+				l.fail(err)
+				return
+			}
+			l.textures[path] = img
+			if density != 1 {
+				l.densities[path] = density
+			}
+		}()
+	}
+
+	for name, path := range audioPaths {
+		go func() {
+			defer l.wg.Done()
+			defer l.done.Add(1)
+			stream, err := decodeAudio(fsys, findAudio(fsys, path))
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if err != nil {
+				l.fail(err)
+				return
+			}
+			l.audio[name] = stream
+		}()
+	}
+
+	return l
+}
+
+func (l *assetLoader) fail(err *assetError) {
+	log.Printf("Warning: %v\n", err)
+	l.failures = append(l.failures, err)
+}
+
+func (l *assetLoader) loaded() int {
+	return int(l.done.Load())
+}
+
+func (l *assetLoader) finished() bool {
+	return l.loaded() == l.total
+}
+
+func (l *assetLoader) wait() {
+	l.wg.Wait()
+}
+
+// decodeTexture loads a texture and returns it with its density. An SVG
+// next to the PNG takes its place, rasterized at scale.
+func decodeTexture(fsys fs.FS, path string, scale float64) (image.Image, float64, *assetError) {
+	if svg := "img/" + svgPath(path); fileExists(fsys, svg) {
+		data, err := loadImage(fsys, svg)
+		if err != nil {
+			return nil, 0, &assetError{path: svg, op: "load", err: err}
+		}
+		img, err := decodeSVG(data, scale)
+		if err != nil {
+			return nil, 0, &assetError{path: svg, op: "rasterize", err: err}
+		}
+		log.Printf("Loaded texture: %s\n", svgPath(path))
+		return img, scale, nil
+	}
+
+	full := "img/" + path
+	imgFile, err := loadImage(fsys, full)
+	if err != nil {
+		return nil, 0, &assetError{path: full, op: "load", err: err}
+	}
+
+	img, _, err := image.Decode(imgFile)
+	if err != nil {
+		return nil, 0, &assetError{path: full, op: "decode", err: err}
+	}
+	log.Printf("Loaded texture: %s\n", path)
+
+	return img, 1, nil
+}
+
+func fileExists(fsys fs.FS, path string) bool {
+	_, err := fs.Stat(fsys, path)
+	return err == nil
+}
+
+func decodeAudio(fsys fs.FS, path string) (audioStream, *assetError) {
+	data, err := loadWav(fsys, path)
+	if err != nil {
+		return nil, &assetError{path: path, op: "load", err: err}
+	}
+
+	dec, err := decodeAudioFile(data, path)
+	if err != nil {
+		return nil, &assetError{path: path, op: "decode", err: err}
+	}
+
+	return dec, nil
+}
@@ -0,0 +1,139 @@
+package hbc
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// In low memory mode textures are kept at half their resolution and drawn
+// scaled up, and the effects that need full-screen buffers of their own, the
+// loop crossfade and the click ripples, are left out. The "netbook" profile
+// turns it on, and the intro falls back to it by itself when the GPU cannot
+// hold the textures at full size.
+
+// halveImage shrinks img to half its size, averaging each 2x2 block.
+func halveImage(img image.Image) *image.RGBA {
+	src := image.NewRGBA(img.Bounds().Sub(img.Bounds().Min))
+	draw.Draw(src, src.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	w, h := max(src.Bounds().Dx()/2, 1), max(src.Bounds().Dy()/2, 1)
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			var sum [4]int
+			n := 0
+			for dy := range 2 {
+				for dx := range 2 {
+					sx, sy := 2*x+dx, 2*y+dy
+					if sx >= src.Bounds().Dx() || sy >= src.Bounds().Dy() {
+						continue
+					}
+					i := src.PixOffset(sx, sy)
+					for c := range sum {
+						sum[c] += int(src.Pix[i+c])
+					}
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			for c := range sum {
+				dst.Pix[i+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
+
+// textureScale is the resolution drawn textures are made at, relative to the
+// scene.
+func (g *Intro) textureScale() float64 {
+	if g.lowMemory {
+		return g.renderScale / 2
+	}
+	return g.renderScale
+}
+
+// uploadTextures turns the decoded images into GPU textures, at half size in
+// low memory mode. Ebiten panics when an image cannot be allocated, which is
+// returned as an error instead.
+func (g *Intro) uploadTextures(l *assetLoader) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	missing := make(map[string]bool)
+	for _, path := range g.textureList() {
+		img, ok := l.textures[path]
+		if !ok {
+			missing[path] = true
+			fallback := fallbackTexture(path)
+			if fallback == nil {
+				// Custom elements have no fallback and are left empty.
+				fallback = ebiten.NewImage(1, 1)
+			}
+			g.textures[path] = fallback
+			continue
+		}
+		density, ok := l.densities[path]
+		if !ok {
+			density = 1
+		}
+		g.uploadTexture(path, img, density)
+	}
+	g.drawBubbleTextures(missing)
+	return nil
+}
+
+// uploadTexture makes a GPU texture of img, which has density pixels per
+// scene pixel, halving it first in low memory mode.
+func (g *Intro) uploadTexture(path string, img image.Image, density float64) *ebiten.Image {
+	if g.lowMemory && img.Bounds().Dx() > 1 && img.Bounds().Dy() > 1 {
+		img = halveImage(img)
+		density /= 2
+	}
+	tex := ebiten.NewImageFromImage(img)
+	g.textures[path] = tex
+	if density != 1 {
+		g.textureDensity[tex] = density
+	}
+	return tex
+}
+
+// releaseTextures frees every texture, before trying again at a lower
+// resolution.
+func (g *Intro) releaseTextures() {
+	for path, img := range g.textures {
+		img.Deallocate()
+		delete(g.textureDensity, img)
+		delete(g.textures, path)
+	}
+}
+
+// loadTextures uploads the textures, retrying in low memory mode if they do
+// not fit. Failing that too is a crash like any other.
+func (g *Intro) loadTextures(l *assetLoader) {
+	if g.lazyTextures {
+		// Only drawn bubbles are made up front; the rest wait until they
+		// are first drawn.
+		if g.cfg.Bubbles.Procedural {
+			g.drawBubbleTextures(nil)
+		}
+		return
+	}
+	err := g.uploadTextures(l)
+	if err != nil && !g.lowMemory {
+		log.Printf("Warning: Could not allocate textures, switching to low memory mode: %v\n", err)
+		g.releaseTextures()
+		g.lowMemory = true
+		err = g.uploadTextures(l)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
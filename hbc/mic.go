@@ -0,0 +1,139 @@
+package hbc
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"os/exec"
+	"runtime"
+	"sync/atomic"
+)
+
+const micSampleRate = 22050
+
+type MicConfig struct {
+	Enabled bool `json:"enabled"`
+	// Command records from the microphone and writes raw signed 16-bit
+	// little endian mono PCM at 22050Hz to stdout. Empty picks arecord on
+	// Linux and ffmpeg on macOS.
+	Command []string `json:"command,omitempty"`
+	// Sensitivity scales the measured loudness before it is clamped to 1.
+	Sensitivity float64 `json:"sensitivity,omitempty"`
+	// MaxRate is the number of bubbles per second released at full volume.
+	MaxRate float64 `json:"max_rate,omitempty"`
+	// WaveGain is how much louder sound stretches the waves' vertical
+	// motion; 1 doubles it at full volume.
+	WaveGain float64 `json:"wave_gain,omitempty"`
+}
+
+func defaultMicCommand() []string {
+	switch runtime.GOOS {
+	case "linux":
+		return []string{"arecord", "-q", "-f", "S16_LE", "-r", "22050", "-c", "1", "-t", "raw"}
+	case "darwin":
+		return []string{"ffmpeg", "-loglevel", "quiet", "-f", "avfoundation", "-i", ":0", "-ac", "1", "-ar", "22050", "-f", "s16le", "-"}
+	}
+	return nil
+}
+
+// micLevel holds the smoothed loudness in [0, 1], shared between the capture
+// goroutine and the game loop.
+type micLevel struct {
+	bits atomic.Uint64
+}
+
+func (m *micLevel) load() float64 {
+	return math.Float64frombits(m.bits.Load())
+}
+
+func (m *micLevel) store(v float64) {
+	m.bits.Store(math.Float64bits(v))
+}
+
+// captureMic runs the capture command and updates level once per 1/60s of
+// audio. Loud sounds raise the level at once; it falls back slowly so bursts
+// of bubbles do not flicker on and off.
+func captureMic(args []string, sensitivity float64, level *micLevel) error {
+	if len(args) == 0 {
+		return errors.New("no capture command configured")
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	defer cmd.Wait()
+	defer cmd.Process.Kill()
+
+	buf := make([]byte, micSampleRate/60*2)
+	var smoothed float64
+	for {
+		if _, err := io.ReadFull(stdout, buf); err != nil {
+			return err
+		}
+
+		var sum float64
+		for i := 0; i < len(buf); i += 2 {
+			s := float64(int16(binary.LittleEndian.Uint16(buf[i:]))) / 32768
+			sum += s * s
+		}
+		rms := math.Min(math.Sqrt(sum/float64(len(buf)/2))*sensitivity, 1)
+
+		if rms > smoothed {
+			smoothed = rms
+		} else {
+			smoothed += (rms - smoothed) * 0.08
+		}
+		level.store(smoothed)
+	}
+}
+
+func (g *Intro) startMic(cfg MicConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	if len(cfg.Command) == 0 {
+		cfg.Command = defaultMicCommand()
+	}
+	if cfg.Sensitivity <= 0 {
+		cfg.Sensitivity = 4
+	}
+	if cfg.MaxRate <= 0 {
+		cfg.MaxRate = 30
+	}
+	if cfg.WaveGain <= 0 {
+		cfg.WaveGain = 1
+	}
+
+	g.mic = &micLevel{}
+	g.micCfg = cfg
+	go func() {
+		err := captureMic(cfg.Command, cfg.Sensitivity, g.mic)
+		log.Printf("Warning: Microphone capture stopped: %v\n", err)
+		g.mic.store(0)
+	}()
+}
+
+// updateMic releases bubbles at a rate following the microphone loudness.
+func (g *Intro) updateMic() {
+	if g.mic == nil {
+		return
+	}
+	g.micBubbles += g.mic.load() * g.micCfg.MaxRate / 60
+	for ; g.micBubbles >= 1; g.micBubbles-- {
+		g.spawnBubble(-1, "")
+	}
+}
+
+// waveAmplitude scales the vertical wave motion by the microphone loudness.
+func (g *Intro) waveAmplitude() float64 {
+	if g.mic == nil {
+		return 1
+	}
+	return 1 + g.mic.load()*g.micCfg.WaveGain
+}
@@ -0,0 +1,108 @@
+package hbc
+
+import (
+	"log"
+)
+
+type MIDIConfig struct {
+	Enabled bool `json:"enabled"`
+	// Device is the raw MIDI device to read, e.g. /dev/snd/midiC1D0 on
+	// Linux, or the input device number on Windows.
+	Device string `json:"device"`
+	// Notes maps note numbers to actions, triggered on note-on.
+	Notes map[uint8]Action `json:"notes"`
+	// CC maps controller numbers to actions, triggered when the value
+	// crosses 64 upwards, the way sustain pedals and buttons switch.
+	CC map[uint8]Action `json:"cc"`
+}
+
+// midiParser turns a raw MIDI byte stream into actions. It handles running
+// status and skips system messages.
+type midiParser struct {
+	cfg     MIDIConfig
+	status  byte
+	data    [2]byte
+	n       int
+	ccState map[uint8]bool
+}
+
+func newMIDIParser(cfg MIDIConfig) *midiParser {
+	return &midiParser{cfg: cfg, ccState: make(map[uint8]bool)}
+}
+
+func (p *midiParser) feed(b byte, emit func(Action)) {
+	switch {
+	case b >= 0xf8:
+		// Real-time messages may appear anywhere and carry no data.
+		return
+	case b >= 0xf0:
+		// System common and sysex cancel running status; their data bytes
+		// are skipped until the next status byte.
+		p.status = 0
+		return
+	case b&0x80 != 0:
+		p.status = b
+		p.n = 0
+		return
+	case p.status == 0:
+		return
+	}
+
+	p.data[p.n] = b
+	p.n++
+	if p.n < midiDataLen(p.status) {
+		return
+	}
+	p.n = 0
+	p.message(p.status, p.data[0], p.data[1], emit)
+}
+
+func midiDataLen(status byte) int {
+	switch status & 0xf0 {
+	case 0xc0, 0xd0:
+		return 1
+	}
+	return 2
+}
+
+func (p *midiParser) message(status, d1, d2 byte, emit func(Action)) {
+	switch status & 0xf0 {
+	case 0x90:
+		if d2 == 0 {
+			return // note-on with velocity 0 is a note-off
+		}
+		if a, ok := p.cfg.Notes[d1]; ok {
+			emit(a)
+		}
+	case 0xb0:
+		on := d2 >= 64
+		if on && !p.ccState[d1] {
+			if a, ok := p.cfg.CC[d1]; ok {
+				emit(a)
+			}
+		}
+		p.ccState[d1] = on
+	}
+}
+
+func (g *Intro) startMIDI(cfg MIDIConfig) {
+	if !cfg.Enabled {
+		return
+	}
+
+	parser := newMIDIParser(cfg)
+	emit := func(a Action) {
+		select {
+		case g.triggers <- a:
+		default:
+		}
+	}
+	go func() {
+		err := readMIDI(cfg.Device, func(b []byte) {
+			for _, c := range b {
+				parser.feed(c, emit)
+			}
+		})
+		log.Printf("Warning: MIDI input %s stopped: %v\n", cfg.Device, err)
+	}()
+}
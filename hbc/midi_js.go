@@ -0,0 +1,7 @@
+package hbc
+
+import "errors"
+
+func readMIDI(device string, handle func([]byte)) error {
+	return errors.New("MIDI input is not available in the browser")
+}
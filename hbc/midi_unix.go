@@ -0,0 +1,32 @@
+//go:build !windows && !js
+
+package hbc
+
+import (
+	"errors"
+	"os"
+)
+
+// readMIDI reads a raw MIDI device node (ALSA's /dev/snd/midiC*D*, or
+// /dev/rmidi* and /dev/umidi* on the BSDs) until it fails.
+func readMIDI(device string, handle func([]byte)) error {
+	if device == "" {
+		return errors.New("no MIDI device configured")
+	}
+	f, err := os.Open(device)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 256)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			handle(buf[:n])
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package hbc
+
+import (
+	"fmt"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	callbackFunction = 0x30000
+	mimData          = 0x3c3
+)
+
+var (
+	winmm       = syscall.NewLazyDLL("winmm.dll")
+	midiInOpen  = winmm.NewProc("midiInOpen")
+	midiInStart = winmm.NewProc("midiInStart")
+)
+
+// readMIDI opens a winmm MIDI input device by number. winmm delivers short
+// messages to a callback with status and data bytes packed into one word;
+// they are unpacked and passed on as a byte stream. It only returns on error.
+func readMIDI(device string, handle func([]byte)) error {
+	id := 0
+	if device != "" {
+		n, err := strconv.Atoi(device)
+		if err != nil {
+			return fmt.Errorf("MIDI device must be a device number on Windows: %w", err)
+		}
+		id = n
+	}
+
+	callback := syscall.NewCallback(func(h, msg, instance, param1, param2 uintptr) uintptr {
+		if msg == mimData {
+			b := []byte{byte(param1), byte(param1 >> 8), byte(param1 >> 16)}
+			if b[0] < 0xf0 {
+				b = b[:1+midiDataLen(b[0])]
+			}
+			handle(b)
+		}
+		return 0
+	})
+
+	var h uintptr
+	if r, _, _ := midiInOpen.Call(uintptr(unsafe.Pointer(&h)), uintptr(id), callback, 0, callbackFunction); r != 0 {
+		return fmt.Errorf("midiInOpen failed with error %d", r)
+	}
+	if r, _, _ := midiInStart.Call(h); r != 0 {
+		return fmt.Errorf("midiInStart failed with error %d", r)
+	}
+
+	select {}
+}
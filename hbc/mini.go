@@ -0,0 +1,78 @@
+package hbc
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Mini mode shrinks the window to a small borderless widget that floats
+// above other windows in a corner of the screen, like picture in picture.
+// It plays a lighter version of the intro: fewer bubbles, no ripples or loop
+// crossfade, and no debug overlay. Clicking it brings the full window back.
+const (
+	miniWidth  = 270
+	miniMargin = 24
+	// miniBubbleShare is the part of the scripted bubbles drawn in mini
+	// mode, on top of whatever the adaptive share leaves.
+	miniBubbleShare = 0.4
+)
+
+type miniMode struct {
+	active    bool
+	requested bool
+
+	// The window as it was, to be put back.
+	width, height        int
+	x, y                 int
+	fullscreen, floating bool
+	decorated, debug     bool
+}
+
+// updateMiniMode runs after the scene's Update, so that the click that ends
+// mini mode is not also taken by the intro.
+func (g *Intro) updateMiniMode() {
+	m := &g.mini
+	switch {
+	case m.requested:
+		m.requested = false
+		if m.active {
+			g.leaveMiniMode()
+		} else {
+			g.enterMiniMode()
+		}
+	case m.active && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
+		g.leaveMiniMode()
+	}
+}
+
+func (g *Intro) enterMiniMode() {
+	m := &g.mini
+	m.fullscreen = ebiten.IsFullscreen()
+	ebiten.SetFullscreen(false)
+	m.width, m.height = ebiten.WindowSize()
+	m.x, m.y = ebiten.WindowPosition()
+	m.floating, m.decorated = ebiten.IsWindowFloating(), ebiten.IsWindowDecorated()
+	m.debug = g.debugMode
+
+	g.debugMode = false
+	g.helpTimer = 0
+	vw, vh := g.viewportSize()
+	w, h := miniWidth, int(miniWidth*vh/vw)
+	ebiten.SetWindowDecorated(false)
+	ebiten.SetWindowFloating(true)
+	ebiten.SetWindowSize(w, h)
+	sw, sh := ebiten.Monitor().Size()
+	ebiten.SetWindowPosition(sw-w-miniMargin, sh-h-miniMargin)
+	m.active = true
+}
+
+func (g *Intro) leaveMiniMode() {
+	m := &g.mini
+	m.active = false
+	g.debugMode = m.debug
+	ebiten.SetWindowDecorated(m.decorated)
+	ebiten.SetWindowFloating(m.floating)
+	ebiten.SetWindowSize(m.width, m.height)
+	ebiten.SetWindowPosition(m.x, m.y)
+	ebiten.SetFullscreen(m.fullscreen)
+}
@@ -0,0 +1,147 @@
+package hbc
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Holding G as the title lands turns the following loop into a game of
+// popping as many bubbles as possible. Pops in quick succession build a
+// combo that multiplies their points.
+
+const (
+	minigameKey = ebiten.KeyG
+	// Pops less than minigameComboFrames apart keep the combo going, up to
+	// minigameMaxCombo times the points.
+	minigameComboFrames = 45
+	minigameMaxCombo    = 10
+	// A bubble is worth minigamePoints divided by its diameter, so small
+	// ones are worth more.
+	minigamePoints = 480
+	// minigameResultFrames is how long the final score stays up.
+	minigameResultFrames = 5 * 60
+	minigameFontSize     = 20
+)
+
+type minigame struct {
+	active   bool
+	score    int
+	combo    int
+	lastPop  int
+	popText  string
+	popTimer int
+	// result counts down while the final score is shown.
+	result    int
+	highScore int
+	newBest   bool
+}
+
+func (g *Intro) setupMinigame() {
+	g.Subscribe(EventTitleLanded, func(Event) {
+		if ebiten.IsKeyPressed(minigameKey) && !g.offline {
+			g.game = minigame{active: true, highScore: g.game.highScore}
+		}
+	})
+	g.Subscribe(EventLoopWrapped, func(Event) {
+		if g.game.active {
+			g.finishMinigame()
+		}
+	})
+}
+
+func (g *Intro) finishMinigame() {
+	m := &g.game
+	m.active = false
+	m.result = minigameResultFrames
+	if m.score > m.highScore {
+		m.highScore = m.score
+		m.newBest = true
+	}
+}
+
+func (g *Intro) updateMinigame() {
+	m := &g.game
+	if m.result > 0 {
+		m.result--
+	}
+	if m.popTimer > 0 {
+		m.popTimer--
+	}
+	if !m.active {
+		return
+	}
+
+	clicked := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	x, y := g.cursorPosition()
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		tx, ty := ebiten.TouchPosition(id)
+		x, y = float64(tx)/g.renderScale+g.viewport.x, float64(ty)/g.renderScale+g.viewport.y
+		clicked = true
+	}
+	if !clicked {
+		return
+	}
+	hit, ok := g.bubbleAt(x, y)
+	if !ok {
+		m.combo = 0
+		return
+	}
+	g.popBubble(hit)
+
+	if m.combo > 0 && g.ticks-m.lastPop <= minigameComboFrames {
+		m.combo = min(m.combo+1, minigameMaxCombo)
+	} else {
+		m.combo = 1
+	}
+	m.lastPop = g.ticks
+	points := int(math.Ceil(minigamePoints/hit.size)) * m.combo
+	m.score += points
+	m.popText = fmt.Sprintf("+%d", points)
+	if m.combo > 1 {
+		m.popText += fmt.Sprintf(" x%d", m.combo)
+	}
+	m.popTimer = minigameComboFrames
+}
+
+func (g *Intro) drawMinigame(ui *ebiten.Image) {
+	m := &g.game
+	face := uiFace(minigameFontSize)
+
+	if m.active {
+		msg := fmt.Sprintf("Score %d", m.score)
+		if m.popTimer > 0 {
+			msg += "   " + m.popText
+		}
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(16, 12)
+		text.Draw(ui, msg, face, op)
+		return
+	}
+	if m.result == 0 {
+		return
+	}
+
+	msg := fmt.Sprintf("Final score %d\nBest %d", m.score, m.highScore)
+	if m.newBest {
+		msg = fmt.Sprintf("Final score %d\nNew best!", m.score)
+	}
+	alpha := min(float64(m.result)/helpFadeFrames, 1)
+	w, h := text.Measure(msg, face, minigameFontSize*1.4)
+	w += helpPadding * 2
+	h += helpPadding * 2
+	x, y := (g.width-w)/2, (g.height-h)/2
+	vector.DrawFilledRect(ui, float32(x), float32(y), float32(w), float32(h), color.RGBA{0, 0, 0, uint8(160 * alpha)}, false)
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(g.width/2, y+helpPadding)
+	op.PrimaryAlign = text.AlignCenter
+	op.LineSpacing = minigameFontSize * 1.4
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	text.Draw(ui, msg, face, op)
+}
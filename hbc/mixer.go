@@ -0,0 +1,145 @@
+package hbc
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	// sfxVoices caps how many sound effects play at once. A burst releases
+	// two dozen bubbles, which would otherwise all pop within a second.
+	sfxVoices = 12
+	sfxVolume = 0.4
+
+	// While sound effects play the music dips by duckDepth, quickly, and
+	// comes back slowly once they stop.
+	duckDepth   = 0.35
+	duckAttack  = 0.5
+	duckRelease = 0.05
+)
+
+// sound is a mono sound effect as samples from -1 to 1.
+type sound []float64
+
+// synth renders a tone whose frequency and amplitude follow shape over the
+// progress from 0 to 1.
+func synth(d time.Duration, shape func(p float64) (freq, amp float64)) sound {
+	s := make(sound, int(d.Seconds()*sampleRate))
+	phase := 0.0
+	for i := range s {
+		freq, amp := shape(float64(i) / float64(len(s)))
+		phase += freq / sampleRate
+		s[i] = math.Sin(2*math.Pi*phase) * amp
+	}
+	return s
+}
+
+var (
+	// blubSound rises like air escaping under water.
+	blubSound = synth(90*time.Millisecond, func(p float64) (float64, float64) {
+		return 300 + 600*p, math.Sin(math.Pi * p)
+	})
+	// popSound is a short falling click for a bubble bursting.
+	popSound = synth(50*time.Millisecond, func(p float64) (float64, float64) {
+		return 1400 - 900*p, (1 - p) * (1 - p)
+	})
+	// hoverSound and clickSound are the Wii menu blips, a faint tick when
+	// the pointer moves onto something and a brighter two-step chirp when
+	// it is clicked.
+	hoverSound = synth(25*time.Millisecond, func(p float64) (float64, float64) {
+		return 2200, 0.5 * (1 - p) * (1 - p)
+	})
+	clickSound = synth(60*time.Millisecond, func(p float64) (float64, float64) {
+		freq := 1320.0
+		if p > 0.4 {
+			freq = 1760
+		}
+		return freq, 0.7 * math.Sin(math.Pi*p)
+	})
+)
+
+// render turns s into 16-bit stereo samples, panned from -1 (left) to 1
+// (right) with equal power so a sound keeps its loudness across the screen.
+func (s sound) render(pan float64) []byte {
+	angle := (min(max(pan, -1), 1) + 1) * math.Pi / 4
+	left, right := math.Cos(angle), math.Sin(angle)
+
+	b := make([]byte, len(s)*4)
+	for i, v := range s {
+		binary.LittleEndian.PutUint16(b[4*i:], uint16(int16(v*left*math.MaxInt16)))
+		binary.LittleEndian.PutUint16(b[4*i+2:], uint16(int16(v*right*math.MaxInt16)))
+	}
+	return b
+}
+
+// mixer plays sound effects over the music, each voice on its own player
+// with its own panning, and the ambience on a channel of its own.
+type mixer struct {
+	voices []*audio.Player
+	duck   float64
+
+	ambience        *audio.Player
+	ambienceSamples []byte
+}
+
+func (m *mixer) prune() {
+	playing := m.voices[:0]
+	for _, p := range m.voices {
+		if p.IsPlaying() {
+			playing = append(playing, p)
+		} else {
+			p.Close()
+		}
+	}
+	m.voices = playing
+}
+
+// updateDucking moves the music volume towards its dipped level while any
+// sound effect plays, and back once none do.
+func (g *Intro) updateDucking() {
+	m := &g.mixer
+	m.prune()
+	target := 0.0
+	if len(m.voices) > 0 {
+		target = duckDepth
+	}
+	if m.duck == target {
+		return
+	}
+
+	rate := duckRelease
+	if target > m.duck {
+		rate = duckAttack
+	}
+	m.duck += (target - m.duck) * rate
+	if math.Abs(target-m.duck) < 0.001 {
+		m.duck = target
+	}
+	g.setVolume(g.baseVolume() * g.shutdownLevel())
+}
+
+// playSound starts s panned to pan, unless sound effects are off or every
+// voice is busy.
+func (g *Intro) playSound(s sound, pan float64) {
+	if !g.cfg.SFX || g.audioContext == nil || g.silent || g.offline {
+		return
+	}
+	g.mixer.prune()
+	if len(g.mixer.voices) >= sfxVoices {
+		return
+	}
+
+	p := g.audioContext.NewPlayerFromBytes(s.render(pan))
+	p.SetVolume(g.baseVolume() * sfxVolume)
+	p.Play()
+	g.mixer.voices = append(g.mixer.voices, p)
+}
+
+// bubblePan places a bubble in the stereo field by its distance from the
+// middle of the screen.
+func (g *Intro) bubblePan(b Bubble) float64 {
+	return b.startX / (g.width / 2)
+}
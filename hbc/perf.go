@@ -0,0 +1,151 @@
+package hbc
+
+import (
+	"fmt"
+	"image/color"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	frameGraphSamples = 240
+	frameGraphHeight  = 60
+	frameGraphScale   = frameGraphHeight / 50.0 // pixels per millisecond
+)
+
+var (
+	frameGraphGood = color.RGBA{80, 200, 80, 200}
+	frameGraphSlow = color.RGBA{230, 190, 40, 200}
+	frameGraphBad  = color.RGBA{220, 60, 60, 200}
+	frameGraphBack = color.RGBA{0, 0, 0, 120}
+	frameGraphLine = color.RGBA{255, 255, 255, 160}
+)
+
+// frameTimes keeps a ring buffer of the time between consecutive draws.
+type frameTimes struct {
+	samples [frameGraphSamples]time.Duration
+	next    int
+	count   int
+	last    time.Time
+}
+
+func (f *frameTimes) tick(now time.Time) {
+	if !f.last.IsZero() {
+		f.samples[f.next] = now.Sub(f.last)
+		f.next = (f.next + 1) % len(f.samples)
+		f.count = min(f.count+1, len(f.samples))
+	}
+	f.last = now
+}
+
+// at returns the i-th oldest sample.
+func (f *frameTimes) at(i int) time.Duration {
+	start := f.next - f.count
+	if start < 0 {
+		start += len(f.samples)
+	}
+	return f.samples[(start+i)%len(f.samples)]
+}
+
+func (f *frameTimes) onePercentLow() float64 {
+	samples := make([]time.Duration, f.count)
+	for i := range samples {
+		samples[i] = f.at(i)
+	}
+	return onePercentLow(samples)
+}
+
+// onePercentLow returns the average frame rate of the slowest 1% of frames.
+// It sorts samples in place.
+func onePercentLow(samples []time.Duration) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	slices.Sort(samples)
+
+	n := max(len(samples)/100, 1)
+	var sum time.Duration
+	for _, d := range samples[len(samples)-n:] {
+		sum += d
+	}
+	avg := sum / time.Duration(n)
+	if avg <= 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(avg)
+}
+
+func (f *frameTimes) draw(screen *ebiten.Image, x, y float32) {
+	w := float32(frameGraphSamples)
+	vector.DrawFilledRect(screen, x, y, w, frameGraphHeight, frameGraphBack, false)
+
+	for i := 0; i < f.count; i++ {
+		ms := float64(f.at(i)) / float64(time.Millisecond)
+		clr := frameGraphGood
+		switch {
+		case ms > 34:
+			clr = frameGraphBad
+		case ms > 17.5:
+			clr = frameGraphSlow
+		}
+		h := float32(min(ms*frameGraphScale, frameGraphHeight))
+		vector.DrawFilledRect(screen, x+float32(i), y+frameGraphHeight-h, 1, h, clr, false)
+	}
+
+	target := y + frameGraphHeight - float32(1000.0/60*frameGraphScale)
+	vector.StrokeLine(screen, x, target, x+w, target, 1, frameGraphLine, false)
+
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("1%% low: %0.1f FPS", f.onePercentLow()), int(x)+2, int(y)+2)
+}
+
+const memStatsInterval = 60
+
+type memStats struct {
+	heapAlloc   uint64
+	heapSys     uint64
+	numGC       uint32
+	lastPause   time.Duration
+	totalPause  time.Duration
+	textureSize uint64
+}
+
+func (g *Intro) sampleMemStats() {
+	g.memStatsTick++
+	if g.memStatsTick%memStatsInterval != 1 {
+		return
+	}
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	g.memStats = memStats{
+		heapAlloc:   ms.HeapAlloc,
+		heapSys:     ms.HeapSys,
+		numGC:       ms.NumGC,
+		lastPause:   time.Duration(ms.PauseNs[(ms.NumGC+255)%256]),
+		totalPause:  time.Duration(ms.PauseTotalNs),
+		textureSize: g.textureBytes(),
+	}
+}
+
+// textureBytes estimates GPU memory used by the loaded textures, assuming four
+// bytes per pixel.
+func (g *Intro) textureBytes() uint64 {
+	var n uint64
+	for _, img := range g.textures {
+		b := img.Bounds()
+		n += uint64(b.Dx()) * uint64(b.Dy()) * 4
+	}
+	return n
+}
+
+func (m memStats) String() string {
+	mib := func(n uint64) float64 { return float64(n) / (1 << 20) }
+	return fmt.Sprintf("Heap: %0.1f / %0.1f MiB\nGC: %d, last pause %s, total %s\nTextures: ~%0.1f MiB",
+		mib(m.heapAlloc), mib(m.heapSys), m.numGC, m.lastPause, m.totalPause, mib(m.textureSize))
+}
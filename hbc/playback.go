@@ -0,0 +1,87 @@
+package hbc
+
+import (
+	"sync"
+	"time"
+)
+
+// Phase is the part of the timeline the intro is in.
+type Phase int
+
+const (
+	// PhaseIntro is the opening, as the waves rise, up to the flash.
+	PhaseIntro Phase = iota
+	// PhaseFlash is the white flash, until the title is fully visible.
+	PhaseFlash
+	// PhaseLoop is the looping banner with the bubbles.
+	PhaseLoop
+)
+
+var phaseNames = map[Phase]string{
+	PhaseIntro: "intro",
+	PhaseFlash: "flash",
+	PhaseLoop:  "loop",
+}
+
+func (p Phase) String() string {
+	return phaseNames[p]
+}
+
+func (t timeline) phase(frame int) Phase {
+	switch {
+	case frame < t.flash:
+		return PhaseIntro
+	case frame < t.titleLanded():
+		return PhaseFlash
+	}
+	return PhaseLoop
+}
+
+// playbackClock is a copy of the frame counters taken at the end of every
+// Update, so that they can be read from other goroutines.
+type playbackClock struct {
+	mu    sync.Mutex
+	frame int
+	loop  int
+}
+
+func (g *Intro) publishPlayback() {
+	c := &g.playback
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frame, c.loop = g.count, g.loopCount
+}
+
+func (g *Intro) playbackFrame() (frame, loop int) {
+	c := &g.playback
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.frame, c.loop
+}
+
+// CurrentFrame is the frame on the timeline, at 60 per second. It counts
+// from the start of the opening and jumps back to the start of the loop
+// each time the loop wraps around. Like the other playback accessors, it may
+// be called from any goroutine and reflects the last Update.
+func (g *Intro) CurrentFrame() int {
+	frame, _ := g.playbackFrame()
+	return frame
+}
+
+// LoopCount is how many times the loop has wrapped around.
+func (g *Intro) LoopCount() int {
+	_, loop := g.playbackFrame()
+	return loop
+}
+
+// Elapsed is how long the intro has played since the opening began, not
+// counting pauses.
+func (g *Intro) Elapsed() time.Duration {
+	frame, loop := g.playbackFrame()
+	return frameTime(frame + loop*(loopEnd-loopStart))
+}
+
+// Phase is the part of the timeline being played.
+func (g *Intro) Phase() Phase {
+	return g.timeline.phase(g.CurrentFrame())
+}
@@ -0,0 +1,180 @@
+package hbc
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// The pointer cursor replaces the system cursor with a Wii remote's hand.
+// Like the original it leans into sideways motion, grows a little over
+// something it can grab, here a bubble, and hides once the mouse rests.
+
+const (
+	// pointerDensity is the pixels per layout pixel the hand is drawn at.
+	pointerDensity = 2
+	// The hand leans by pointerLean radians per pixel of motion a frame, up
+	// to pointerMaxTilt, easing towards it by pointerTiltEase.
+	pointerLean     = 0.02
+	pointerMaxTilt  = 0.3
+	pointerTiltEase = 0.2
+	// pointerRestTilt is the slight lean of the hand held still.
+	pointerRestTilt = -0.12
+	pointerHover    = 1.12
+	// pointerIdleFrames is how long the hand stays after the mouse stops,
+	// fading out over pointerFadeFrames.
+	pointerIdleFrames = 4 * 60
+	pointerFadeFrames = 30
+	// The shadow falls down and to the right of the hand.
+	pointerShadowX, pointerShadowY = 4, 6
+	pointerShadowAlpha             = 0.3
+)
+
+var (
+	pointerFill    = color.RGBA{255, 255, 255, 255}
+	pointerOutline = color.RGBA{40, 160, 215, 255}
+)
+
+type pointer struct {
+	img          *ebiten.Image
+	x, y         float64
+	tilt, scale  float64
+	idle         int
+	inside       bool
+	cursorHidden bool
+	// hovered is the bubble under the pointer, for the hover sound.
+	hovered *bubbleHit
+}
+
+// pointerPath is the hand in layout pixels with the fingertip at the origin:
+// the index finger up, the other fingers folded and the thumb out to the
+// left.
+func pointerPath() *vector.Path {
+	var p vector.Path
+	p.MoveTo(-5, 5)
+	p.Arc(0, 5, 5, math.Pi, 0, vector.Clockwise)
+	p.LineTo(5, 19)
+	p.QuadTo(13, 16, 15, 21)
+	p.QuadTo(22, 19, 23, 25)
+	p.QuadTo(29, 24, 29, 31)
+	p.LineTo(29, 40)
+	p.QuadTo(28, 51, 16, 53)
+	p.LineTo(4, 53)
+	p.QuadTo(-5, 52, -8, 44)
+	p.LineTo(-14, 33)
+	p.QuadTo(-16, 27, -10, 27)
+	p.LineTo(-5, 32)
+	p.Close()
+	return &p
+}
+
+// pointerOrigin is where the fingertip lies in the hand's image.
+const pointerOriginX, pointerOriginY = 18, 3
+
+func pointerImage() *ebiten.Image {
+	img := ebiten.NewImage(50*pointerDensity, 60*pointerDensity)
+	p := pointerPath()
+
+	var geoM ebiten.GeoM
+	geoM.Translate(pointerOriginX, pointerOriginY)
+	geoM.Scale(pointerDensity, pointerDensity)
+
+	fillVerticesGeoM(img, p, nil, geoM, pointerFill)
+	fillVerticesGeoM(img, p, &vector.StrokeOptions{Width: 2.5, LineJoin: vector.LineJoinRound}, geoM, pointerOutline)
+	return img
+}
+
+func (g *Intro) updatePointer() {
+	if g.offline || g.kiosk {
+		return
+	}
+	p := &g.pointer
+	x, y := g.cursorPosition()
+	inside := x >= 0 && y >= 0 && x < g.width && y < g.height
+	var hit bubbleHit
+	var over bool
+	if inside {
+		hit, over = g.bubbleAt(x, y)
+	}
+	g.updatePointerSounds(x, hit, over)
+	if !g.cfg.PointerCursor {
+		return
+	}
+	if !p.cursorHidden {
+		ebiten.SetCursorMode(ebiten.CursorModeHidden)
+		p.cursorHidden = true
+	}
+
+	dx := x - p.x
+	if dx != 0 || y != p.y {
+		p.idle = 0
+	} else {
+		p.idle++
+	}
+	p.x, p.y, p.inside = x, y, inside
+
+	tilt := pointerRestTilt + min(max(dx*pointerLean, -pointerMaxTilt), pointerMaxTilt)
+	if g.reducedMotion {
+		tilt = pointerRestTilt
+	}
+	p.tilt += (tilt - p.tilt) * pointerTiltEase
+
+	scale := 1.0
+	if over {
+		scale = pointerHover
+	}
+	if p.scale == 0 {
+		p.scale = scale
+	}
+	p.scale += (scale - p.scale) * 0.3
+}
+
+// updatePointerSounds ticks when the pointer moves onto a bubble and chirps
+// when one is clicked. Pops in the minigame make their own sound.
+func (g *Intro) updatePointerSounds(x float64, hit bubbleHit, over bool) {
+	p := &g.pointer
+	pan := (x - g.width/2) / (g.width / 2)
+	// The app grid plays its own sounds for its tiles.
+	if !over || g.appGridOpen {
+		p.hovered = nil
+		return
+	}
+	if p.hovered == nil || p.hovered.live != hit.live || p.hovered.index != hit.index {
+		g.playSound(hoverSound, pan)
+	}
+	p.hovered = &hit
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && !g.game.active {
+		g.playSound(clickSound, pan)
+	}
+}
+
+func (g *Intro) drawPointer(ui *ebiten.Image) {
+	p := &g.pointer
+	if !g.cfg.PointerCursor || g.offline || g.kiosk || !p.inside || p.idle >= pointerIdleFrames {
+		return
+	}
+	if p.img == nil {
+		p.img = pointerImage()
+	}
+	alpha := min(float64(pointerIdleFrames-p.idle)/pointerFadeFrames, 1)
+
+	draw := func(dx, dy float64, shadow bool) {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(1.0/pointerDensity, 1.0/pointerDensity)
+		op.GeoM.Translate(-pointerOriginX, -pointerOriginY)
+		op.GeoM.Scale(p.scale, p.scale)
+		op.GeoM.Rotate(p.tilt)
+		op.GeoM.Translate(p.x+dx, p.y+dy)
+		if shadow {
+			op.ColorScale.Scale(0, 0, 0, pointerShadowAlpha)
+		}
+		op.ColorScale.ScaleAlpha(float32(alpha))
+		op.Filter = ebiten.FilterLinear
+		ui.DrawImage(p.img, op)
+	}
+	draw(pointerShadowX, pointerShadowY, true)
+	draw(0, 0, false)
+}
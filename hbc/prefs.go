@@ -0,0 +1,100 @@
+package hbc
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// preferences are the settings a user changes while watching, as opposed to
+// the config file they write by hand. They are saved on exit and win over
+// the config file on the next start; command line flags win over both.
+type preferences struct {
+	Volume        float64 `json:"volume"`
+	Theme         string  `json:"theme"`
+	Fullscreen    bool    `json:"fullscreen"`
+	ReducedMotion bool    `json:"reduced_motion"`
+	AudioOffsetMS int64   `json:"audio_offset_ms"`
+	HighScore     int     `json:"high_score,omitempty"`
+}
+
+func preferencesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-hbc-intro", "preferences.json"), nil
+}
+
+func (g *Intro) loadPreferences() {
+	path, err := preferencesPath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	var p preferences
+	if err == nil {
+		err = json.Unmarshal(data, &p)
+	}
+	if err != nil {
+		log.Printf("Warning: Could not load preferences: %v\n", err)
+		return
+	}
+
+	g.volume = min(max(p.Volume, 0), 1)
+	g.setTheme(p.Theme)
+	ebiten.SetFullscreen(p.Fullscreen)
+	g.reducedMotion = p.ReducedMotion
+	g.audioOffset = time.Duration(p.AudioOffsetMS) * time.Millisecond
+	g.game.highScore = p.HighScore
+}
+
+func (g *Intro) savePreferences() error {
+	p := preferences{
+		Volume:        g.volume,
+		Theme:         g.theme().Name,
+		Fullscreen:    ebiten.IsFullscreen(),
+		ReducedMotion: g.reducedMotion,
+		AudioOffsetMS: g.audioOffset.Milliseconds(),
+		HighScore:     g.game.highScore,
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path, err := preferencesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// motionScale shrinks the swaying of the waves and title with reduced motion.
+func (g *Intro) motionScale() float64 {
+	if g.reducedMotion {
+		return 0.3
+	}
+	return 1
+}
+
+// flashStrength dims the white flashes with reduced motion, which also
+// spares people sensitive to sudden bright frames.
+func (g *Intro) flashStrength() float32 {
+	if g.reducedMotion {
+		return 0.35
+	}
+	return 1
+}
@@ -0,0 +1,118 @@
+package hbc
+
+import (
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Profile trades visual quality for speed on weak hardware.
+type Profile struct {
+	// BubbleDensity scales the number of generated bubbles.
+	BubbleDensity float64
+	// RenderScale is the internal resolution relative to 810x456. The
+	// result is stretched to the window.
+	RenderScale float64
+	// ScreenFilter smooths that final stretch, in App.DrawFinalScreen.
+	// Turning it off uses nearest filtering, which is cheaper.
+	ScreenFilter bool
+	// LowMemory keeps textures at half resolution and leaves out the
+	// effects that need buffers of their own.
+	LowMemory bool
+	// NoShaders leaves out the effects that cost a shader or another pass
+	// over the whole screen: the click ripples and the loop crossfade.
+	NoShaders bool
+}
+
+var profiles = map[string]Profile{
+	"default": {BubbleDensity: 1, RenderScale: 1, ScreenFilter: true},
+	// Keeps a Raspberry Pi driving a TV at 60fps.
+	"pi": {BubbleDensity: 0.4, RenderScale: 0.5, ScreenFilter: false, NoShaders: true},
+	// For old netbooks and boards with little video memory.
+	"netbook": {BubbleDensity: 0.6, RenderScale: 0.75, ScreenFilter: true, LowMemory: true},
+}
+
+func (g *Intro) applyProfile(name string) {
+	if name == "" {
+		name = "default"
+	}
+	p, ok := profiles[name]
+	if !ok {
+		log.Printf("Warning: Unknown profile %q, using default\n", name)
+		p = profiles["default"]
+	}
+
+	g.bubbleDensity = p.BubbleDensity
+	g.setRenderScale(p.RenderScale)
+	g.screenFilter = p.ScreenFilter
+	g.lowMemory = p.LowMemory
+	g.noShaders = p.NoShaders
+}
+
+// setRenderScale changes the internal resolution. Everything in the world is
+// drawn through g.view, so only the size returned by Layout and the view
+// transform have to change. The view also moves the viewport, if any, to the
+// top left corner.
+func (g *Intro) setRenderScale(scale float64) {
+	g.renderScale = scale
+	g.view.Reset()
+	g.view.Translate(-g.viewport.x, -g.viewport.y)
+	g.view.Scale(scale, scale)
+}
+
+// The render scale can be changed while running in steps of
+// renderScaleStep, from half to twice the logical size.
+const (
+	minRenderScale  = 0.5
+	maxRenderScale  = 2
+	renderScaleStep = 0.25
+)
+
+func (g *Intro) stepRenderScale(dir int) {
+	g.setRenderScale(min(max(g.renderScale+float64(dir)*renderScaleStep, minRenderScale), maxRenderScale))
+}
+
+func (g *Intro) renderSize() (int, int) {
+	w, h := g.viewportSize()
+	return int(math.Ceil(w * g.renderScale)), int(math.Ceil(h * g.renderScale))
+}
+
+func (g *Intro) drawsThroughView() {}
+
+func (g *Intro) drawImage(dst, img *ebiten.Image, op *ebiten.DrawImageOptions) {
+	if d, ok := g.textureDensity[img]; ok {
+		var geoM ebiten.GeoM
+		geoM.Scale(1/d, 1/d)
+		geoM.Concat(op.GeoM)
+		op.GeoM = geoM
+	}
+	op.GeoM.Concat(g.view)
+	if op.Blend == (ebiten.Blend{}) {
+		op.Blend = g.blend
+	}
+	dst.DrawImage(img, op)
+}
+
+// uiLayer returns the image overlays are drawn to. Overlays are laid out at
+// the logical size, so at other render scales or in a viewport they go to an
+// offscreen image that flushUILayer then draws onto the screen through the view.
+func (g *Intro) uiLayer(screen *ebiten.Image) *ebiten.Image {
+	if g.renderScale == 1 && g.viewport.width == 0 {
+		return screen
+	}
+	if g.ui == nil {
+		g.ui = ebiten.NewImage(g.layoutSize())
+	}
+	g.ui.Clear()
+	return g.ui
+}
+
+func (g *Intro) flushUILayer(screen, ui *ebiten.Image) {
+	if ui == screen {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.Filter = ebiten.FilterLinear
+	g.drawImage(screen, ui, op)
+}
@@ -0,0 +1,56 @@
+package hbc
+
+import (
+	"flag"
+	"log"
+	"net/url"
+	"strings"
+	"syscall/js"
+	"unicode"
+)
+
+// queryFlags turns the page's query string into flags of fset, so that a
+// link such as ?theme=night&skipIntro=1&seed=42 customizes a hosted intro.
+// Names may be written as the flags are or in camel case; a name without a
+// value sets a boolean flag. Unknown names are left out with a warning
+// rather than stopping the program.
+func queryFlags(fset *flag.FlagSet) []string {
+	search := js.Global().Get("location").Get("search").String()
+	query, err := url.ParseQuery(strings.TrimPrefix(search, "?"))
+	if err != nil {
+		log.Printf("Warning: Could not parse the page URL: %v\n", err)
+		return nil
+	}
+
+	var args []string
+	for key, values := range query {
+		name := kebabCase(key)
+		f := fset.Lookup(name)
+		if f == nil {
+			log.Printf("Warning: Unknown URL parameter %q\n", key)
+			continue
+		}
+		value := values[len(values)-1]
+		if value == "" {
+			args = append(args, "-"+f.Name)
+		} else {
+			args = append(args, "-"+f.Name+"="+value)
+		}
+	}
+	return args
+}
+
+// kebabCase turns skipIntro into skip-intro.
+func kebabCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,10 @@
+//go:build !js
+
+package hbc
+
+import "flag"
+
+// queryFlags only has a page URL to read in the browser.
+func queryFlags(fset *flag.FlagSet) []string {
+	return nil
+}
@@ -0,0 +1,268 @@
+package hbc
+
+import (
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// prepareOffline turns off everything that only makes sense when watching the
+// intro live: audio, overlays and the startup help.
+func (g *Intro) prepareOffline() {
+	g.offline = true
+	g.silent = true
+	g.debugMode = false
+	g.helpTimer = 0
+}
+
+// offlineScene renders the frames [frame, end) one per Draw call and hands
+// each of them to capture, independent of the wall clock.
+type offlineScene struct {
+	game    *Intro
+	frame   int
+	end     int
+	capture func(frame int, img *image.RGBA) error
+	err     error
+}
+
+func (s *offlineScene) Update() error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.frame >= s.end || s.game.quitRequested.Load() || ebiten.IsWindowBeingClosed() {
+		return ebiten.Termination
+	}
+	return nil
+}
+
+func (s *offlineScene) Draw(screen *ebiten.Image) {
+	if s.err != nil || s.frame >= s.end {
+		return
+	}
+
+	s.game.count = s.frame
+	s.game.Draw(screen)
+
+	img := image.NewRGBA(screen.Bounds())
+	screen.ReadPixels(img.Pix)
+	if err := s.capture(s.frame, img); err != nil {
+		s.err = err
+		return
+	}
+	s.frame++
+}
+
+func (s *offlineScene) drawsThroughView() {}
+
+func runExportFrame(args []string) error {
+	fset, common := newFlagSet("export-frame")
+	frame := fset.Int("frame", startBoom+60, "frame to render")
+	out := fset.String("o", "frame.png", "output PNG file")
+	fset.Parse(args)
+
+	if *frame < 0 || *frame >= loopEnd {
+		return fmt.Errorf("frame %d is outside [0, %d)", *frame, loopEnd)
+	}
+
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	g.prepareOffline()
+
+	scene := &offlineScene{
+		game:  g,
+		frame: *frame,
+		end:   *frame + 1,
+		capture: func(frame int, img *image.RGBA) error {
+			return writePNG(*out, img)
+		},
+	}
+	if err := runScene(g, "go-hbc-intro export", func() Scene { return scene }); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote frame %d to %s\n", *frame, *out)
+	return nil
+}
+
+func runRecord(args []string) error {
+	fset, common := newFlagSet("record")
+	from := fset.Int("from", loopStart, "first frame to record")
+	to := fset.Int("to", loopEnd, "frame after the last one to record")
+	step := fset.Int("step", 2, "record every n-th frame")
+	out := fset.String("o", "hbc-loop.gif", "output GIF or APNG (.png, .apng) file, or a directory for a PNG sequence")
+	fset.Parse(args)
+
+	if *from < 0 || *to > loopEnd || *from >= *to {
+		return fmt.Errorf("invalid frame range [%d, %d)", *from, *to)
+	}
+	if *step < 1 {
+		return fmt.Errorf("invalid step %d", *step)
+	}
+
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	g.prepareOffline()
+
+	var sink frameSink
+	switch strings.ToLower(filepath.Ext(*out)) {
+	case ".gif":
+		sink = &gifSink{path: *out, step: *step}
+	case ".png", ".apng":
+		sink = &apngSink{path: *out, step: *step}
+	default:
+		if err := os.MkdirAll(*out, 0o755); err != nil {
+			return err
+		}
+		sink = &pngSequenceSink{dir: *out}
+	}
+
+	scene := &offlineScene{
+		game:  g,
+		frame: *from,
+		end:   *to,
+		capture: func(frame int, img *image.RGBA) error {
+			if (frame-*from)%*step != 0 {
+				return nil
+			}
+			return sink.add(frame, img)
+		},
+	}
+	if err := runScene(g, "go-hbc-intro recording", func() Scene { return scene }); err != nil {
+		return err
+	}
+	if scene.frame < scene.end {
+		return fmt.Errorf("recording interrupted at frame %d", scene.frame)
+	}
+	if err := sink.close(); err != nil {
+		return err
+	}
+	fmt.Printf("Recorded frames %d-%d to %s\n", *from, *to-1, *out)
+	return nil
+}
+
+type frameSink interface {
+	add(frame int, img *image.RGBA) error
+	close() error
+}
+
+type pngSequenceSink struct {
+	dir string
+}
+
+func (s *pngSequenceSink) add(frame int, img *image.RGBA) error {
+	return writePNG(filepath.Join(s.dir, fmt.Sprintf("frame_%05d.png", frame)), img)
+}
+
+func (s *pngSequenceSink) close() error {
+	return nil
+}
+
+// gifSink collects dithered frames and writes a looping GIF on close. GIF
+// delays are in hundredths of a second, so they are spread to keep the
+// overall speed at 60 ticks per second.
+type gifSink struct {
+	path string
+	step int
+	anim gif.GIF
+}
+
+func (s *gifSink) add(frame int, img *image.RGBA) error {
+	p := image.NewPaletted(img.Bounds(), palette.Plan9)
+	draw.FloydSteinberg.Draw(p, img.Bounds(), img, image.Point{})
+
+	n := len(s.anim.Image)
+	at := func(i int) int { return int(math.Round(float64(i*s.step) * 100 / 60)) }
+	s.anim.Image = append(s.anim.Image, p)
+	s.anim.Delay = append(s.anim.Delay, at(n+1)-at(n))
+	return nil
+}
+
+func (s *gifSink) close() error {
+	f, err := os.Create(s.path)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(f, &s.anim); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// benchScene runs the intro with vsync off and records how long each frame
+// took until enough samples have been collected.
+type benchScene struct {
+	game   *Intro
+	frames int
+	times  []time.Duration
+	last   time.Time
+}
+
+func (s *benchScene) Update() error {
+	if len(s.times) >= s.frames {
+		return ebiten.Termination
+	}
+	return s.game.Update()
+}
+
+func (s *benchScene) Draw(screen *ebiten.Image) {
+	now := time.Now()
+	if !s.last.IsZero() {
+		s.times = append(s.times, now.Sub(s.last))
+	}
+	s.last = now
+	s.game.Draw(screen)
+}
+
+func (s *benchScene) drawsThroughView() {}
+
+func runBench(args []string) error {
+	fset, common := newFlagSet("bench")
+	frames := fset.Int("frames", 1200, "number of frames to measure")
+	fset.Parse(args)
+
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	g.silent = true
+	g.debugMode = false
+	g.helpTimer = 0
+
+	scene := &benchScene{game: g, frames: *frames}
+	next := func() Scene {
+		ebiten.SetVsyncEnabled(false)
+		ebiten.SetTPS(ebiten.SyncWithFPS)
+		return scene
+	}
+	if err := runScene(g, "go-hbc-intro bench", next); err != nil {
+		return err
+	}
+	if len(scene.times) == 0 {
+		return fmt.Errorf("no frames measured")
+	}
+
+	var total, worst time.Duration
+	for _, d := range scene.times {
+		total += d
+		worst = max(worst, d)
+	}
+	avg := total / time.Duration(len(scene.times))
+	fmt.Printf("Frames:   %d\n", len(scene.times))
+	fmt.Printf("Average:  %s (%.1f FPS)\n", avg, float64(time.Second)/float64(avg))
+	fmt.Printf("1%% low:   %.1f FPS\n", onePercentLow(scene.times))
+	fmt.Printf("Worst:    %s\n", worst)
+	return nil
+}
@@ -0,0 +1,40 @@
+package hbc
+
+import (
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// maxBrowserScale caps the render scale on high density or zoomed pages.
+const maxBrowserScale = 4
+
+// fitToWindow follows the canvas in the browser, which resizes with the
+// page. Without a configured aspect ratio the scene is composed for the
+// canvas's own shape, as setupLayout does for a given ratio, and the render
+// scale is set so that one rendered pixel is one device pixel, keeping the
+// picture sharp on high density screens and when the page is zoomed. It
+// only acts when the size or pixel ratio changes, so the render scale keys
+// still work in between.
+func (g *Intro) fitToWindow(w, h int) {
+	dpr := ebiten.Monitor().DeviceScaleFactor()
+	size := [3]float64{float64(w), float64(h), dpr}
+	if size == g.windowFit || w <= 0 || h <= 0 {
+		return
+	}
+	g.windowFit = size
+
+	if g.cfg.Aspect == "" {
+		oldW, oldH := g.width, g.height
+		g.setupLayout(strconv.FormatFloat(float64(w)/float64(h), 'f', -1, 64))
+		if g.width != oldW || g.height != oldH {
+			// Overlays and bubbles are laid out for the old size.
+			g.ui = nil
+			g.setupViewport(g.cfg.Wall.Viewport)
+			g.generateBubbles()
+		}
+	}
+	vw, vh := g.viewportSize()
+	fit := min(float64(w)/vw, float64(h)/vh)
+	g.setRenderScale(min(max(fit*dpr, minRenderScale), maxBrowserScale))
+}
@@ -0,0 +1,7 @@
+//go:build !js
+
+package hbc
+
+// fitToWindow leaves desktop windows to ebiten's own stretching; the render
+// scale there is set by the profile and the settings.
+func (g *Intro) fitToWindow(w, h int) {}
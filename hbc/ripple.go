@@ -0,0 +1,129 @@
+package hbc
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Clicking the water sends out a ripple that bends the waves and bubbles
+// around it for a moment. The rings are a shader over the layers below the
+// title, so the title itself stays still.
+
+const (
+	// maxRipples matches the array size in rippleShaderSrc; the oldest
+	// ripple gives way to a new one.
+	maxRipples = 8
+	// rippleFrames is how long a ripple lasts, spreading by rippleSpeed
+	// pixels per frame. rippleStrength is how far it shifts the picture at
+	// first, in pixels.
+	rippleFrames   = 90
+	rippleSpeed    = 4
+	rippleStrength = 6
+)
+
+const rippleShaderSrc = `//kage:unit pixels
+
+package main
+
+// Ripples holds the center, radius and strength of each ripple in
+// destination pixels. Unused entries have no strength.
+var Ripples [8]vec4
+// Width is the width of a ring in pixels.
+var Width float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	offset := vec2(0)
+	for i := 0; i < 8; i++ {
+		r := Ripples[i]
+		d := dstPos.xy - r.xy
+		dist := length(d)
+		if dist > 0.5 {
+			wave := dist - r.z
+			ring := exp(-wave * wave / (2 * Width * Width))
+			offset += d / dist * sin(wave/Width*3.14159) * ring * r.w
+		}
+	}
+	return imageSrc0At(srcPos + offset)
+}
+`
+
+type ripple struct {
+	x, y float64
+	age  int
+}
+
+var rippleShader *ebiten.Shader
+
+func loadRippleShader() *ebiten.Shader {
+	if rippleShader == nil {
+		s, err := ebiten.NewShader([]byte(rippleShaderSrc))
+		if err != nil {
+			log.Printf("Warning: Could not compile ripple shader: %v\n", err)
+			return nil
+		}
+		rippleShader = s
+	}
+	return rippleShader
+}
+
+// waterLevel is the height of the water surface, which rises as the waves
+// settle.
+func (g *Intro) waterLevel() float64 {
+	aniProgress := g.timeline.riseProgress(g.count)
+	return (140-g.height)*aniProgress + g.height
+}
+
+func (g *Intro) updateRipples() {
+	live := g.ripples[:0]
+	for _, r := range g.ripples {
+		if r.age++; r.age < rippleFrames {
+			live = append(live, r)
+		}
+	}
+	g.ripples = live
+
+	if g.reducedMotion || g.lowMemory || g.noShaders || g.mini.active || g.kiosk || !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+	x, y := g.cursorPosition()
+	if y < g.waterLevel() {
+		return
+	}
+	if len(g.ripples) == maxRipples {
+		g.ripples = g.ripples[1:]
+	}
+	g.ripples = append(g.ripples, ripple{x: x, y: y})
+}
+
+// drawRippled draws src, the layers below the title, to dst bent by the
+// ripples.
+func (g *Intro) drawRippled(dst, src *ebiten.Image) {
+	shader := loadRippleShader()
+	if shader == nil {
+		dst.DrawImage(src, nil)
+		return
+	}
+
+	// The ripples are in layout coordinates and the shader works in the
+	// pixels of src, which start at the viewport.
+	s := g.renderScale
+	var uniform [maxRipples * 4]float32
+	for i, r := range g.ripples {
+		fade := 1 - float64(r.age)/rippleFrames
+		uniform[4*i] = float32((r.x - g.viewport.x) * s)
+		uniform[4*i+1] = float32((r.y - g.viewport.y) * s)
+		uniform[4*i+2] = float32(float64(r.age) * rippleSpeed * s)
+		uniform[4*i+3] = float32(rippleStrength * fade * fade * s)
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Images[0] = src
+	op.Uniforms = map[string]any{
+		"Ripples": uniform[:],
+		"Width":   float32(12 * s),
+	}
+	dst.DrawRectShader(w, h, shader, op)
+}
@@ -0,0 +1,248 @@
+package hbc
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+type Scene interface {
+	Update() error
+	Draw(screen *ebiten.Image)
+}
+
+// viewScene is implemented by scenes that draw through Intro.view and so
+// already handle the render scale themselves.
+type viewScene interface {
+	drawsThroughView()
+}
+
+// App is the ebiten.Game driving whichever scene is current.
+type App struct {
+	scene   Scene
+	game    *Intro
+	canvas  *ebiten.Image
+	frame   *ebiten.Image
+	crashed atomic.Bool
+
+	// home is the scene that plays the intro, the game itself or a scene
+	// wrapping it, which the credits and the app grid return to.
+	home Scene
+	// kiosk restarts the intro on errors in kiosk mode; nil otherwise.
+	kiosk *kioskWatchdog
+}
+
+func (a *App) SetScene(s Scene) {
+	a.scene = s
+}
+
+func (a *App) Update() (err error) {
+	defer a.recoverCrash()
+	if a.kiosk != nil {
+		defer a.kiosk.recoverUpdate(&err)
+		a.kiosk.update()
+	}
+	if err := a.scene.Update(); err != nil {
+		if a.kiosk != nil {
+			return a.kiosk.updateFailed(err)
+		}
+		return err
+	}
+	a.startCredits()
+	a.startApps()
+	a.game.updateMiniMode()
+	return nil
+}
+
+// Draw renders the current scene. Scenes other than the intro itself are laid
+// out for 810x456 and get scaled to the render size and centered in the
+// layout.
+func (a *App) Draw(screen *ebiten.Image) {
+	defer a.recoverCrash()
+	if a.kiosk != nil {
+		defer a.kiosk.recoverDraw()
+	}
+
+	// A frame left out by the frame limiter keeps the last one on screen,
+	// so runScene turns off ebiten's clearing and the screen is cleared
+	// here instead.
+	if a.game.frameLimit.skip(time.Now()) {
+		return
+	}
+	screen.Clear()
+	if a.game.cfg.IntegerScale {
+		a.drawIntegerScaled(screen)
+		return
+	}
+	a.drawScene(screen)
+}
+
+func (a *App) drawScene(screen *ebiten.Image) {
+	native := a.game.renderScale == 1 && a.game.width == screenWidth && a.game.height == screenHeight
+	if _, ok := a.scene.(viewScene); ok || native {
+		a.scene.Draw(screen)
+		return
+	}
+
+	if a.canvas == nil {
+		a.canvas = ebiten.NewImage(screenWidth, screenHeight)
+	}
+	a.canvas.Clear()
+	a.scene.Draw(a.canvas)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate((a.game.width-screenWidth)/2, (a.game.height-screenHeight)/2)
+	op.Filter = ebiten.FilterLinear
+	a.game.drawImage(screen, a.canvas, op)
+}
+
+// drawIntegerScaled renders the scene at the render size and enlarges it by
+// the largest whole factor that fits the window, centered on black, so every
+// source pixel becomes an exact block of screen pixels.
+func (a *App) drawIntegerScaled(screen *ebiten.Image) {
+	w, h := a.game.renderSize()
+	if a.frame == nil || a.frame.Bounds().Dx() != w || a.frame.Bounds().Dy() != h {
+		a.frame = ebiten.NewImage(w, h)
+	}
+	a.frame.Clear()
+	a.drawScene(a.frame)
+
+	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
+	scale := max(min(sw/w, sh/h), 1)
+
+	screen.Fill(color.Black)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(scale), float64(scale))
+	op.GeoM.Translate(float64((sw-w*scale)/2), float64((sh-h*scale)/2))
+	screen.DrawImage(a.frame, op)
+}
+
+// screenShaderSrc is the box filter ebiten stretches the screen with by
+// default, which keeps pixels sharp when scaling up by an odd factor.
+const screenShaderSrc = `//kage:unit pixels
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2) vec4 {
+	// Average the texels under a destination pixel, a square 1/scale wide.
+	scale := imageDstSize() / imageSrc0Size()
+	p0 := srcPos - 1/2.0/scale
+	p1 := srcPos + 1/2.0/scale
+
+	c0 := imageSrc0UnsafeAt(p0)
+	c1 := imageSrc0UnsafeAt(vec2(p1.x, p0.y))
+	c2 := imageSrc0UnsafeAt(vec2(p0.x, p1.y))
+	c3 := imageSrc0UnsafeAt(p1)
+
+	rate := clamp(fract(p1)*scale, 0, 1)
+	return mix(mix(c0, c1, rate.x), mix(c2, c3, rate.x), rate.y)
+}
+`
+
+var screenShader *ebiten.Shader
+
+func loadScreenShader() *ebiten.Shader {
+	if screenShader == nil {
+		s, err := ebiten.NewShader([]byte(screenShaderSrc))
+		if err != nil {
+			log.Printf("Warning: Could not compile screen shader: %v\n", err)
+			return nil
+		}
+		screenShader = s
+	}
+	return screenShader
+}
+
+// DrawFinalScreen stretches the rendered frame to the window. Like ebiten's
+// own final screen it scales by whole factors and, with the screen filter
+// off, by any factor with nearest filtering; otherwise it filters linearly
+// when shrinking and with the box filter when enlarging.
+func (a *App) DrawFinalScreen(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+	scale := geoM.Element(0, 0)
+	op := &ebiten.DrawImageOptions{GeoM: geoM}
+	switch {
+	case !a.game.screenFilter, math.Floor(scale) == scale:
+	case scale < 1:
+		op.Filter = ebiten.FilterLinear
+	default:
+		if s := loadScreenShader(); s != nil {
+			sop := &ebiten.DrawRectShaderOptions{GeoM: geoM}
+			sop.Images[0] = offscreen
+			b := offscreen.Bounds()
+			screen.DrawRectShader(b.Dx(), b.Dy(), s, sop)
+			return
+		}
+		op.Filter = ebiten.FilterLinear
+	}
+	screen.DrawImage(offscreen, op)
+}
+
+// Layout uses the render size, which ebiten then stretches to the window. In
+// the browser the layout and render scale follow the canvas first.
+// With integer scaling the screen is the window itself in device pixels and
+// drawIntegerScaled does the stretching.
+func (a *App) Layout(outsideWidth, outsideHeight int) (int, int) {
+	if a.game.cfg.IntegerScale {
+		s := ebiten.Monitor().DeviceScaleFactor()
+		return int(float64(outsideWidth) * s), int(float64(outsideHeight) * s)
+	}
+	a.game.fitToWindow(outsideWidth, outsideHeight)
+	return a.game.renderSize()
+}
+
+// loadingScene shows a progress bar while the assets decode in the
+// background, then hands over to the scene returned by next.
+type loadingScene struct {
+	app    *App
+	game   *Intro
+	loader *assetLoader
+	next   func() Scene
+}
+
+func newLoadingScene(app *App, g *Intro, next func() Scene) *loadingScene {
+	g.setupTextureCache()
+	return &loadingScene{
+		app:    app,
+		game:   g,
+		loader: startAssetLoader(g.assets, g.eagerTextures(), g.renderScale),
+		next:   next,
+	}
+}
+
+func (s *loadingScene) Update() error {
+	if s.game.quitRequested.Load() || ebiten.IsWindowBeingClosed() {
+		return ebiten.Termination
+	}
+	if s.loader.finished() {
+		if s.game.strict && len(s.loader.failures) > 0 {
+			s.app.SetScene(newAssetErrorScene(s.game, s.loader.failures))
+			return nil
+		}
+		s.game.finishLoading(s.loader)
+		s.app.home = s.next()
+		s.app.SetScene(s.app.home)
+	}
+	return nil
+}
+
+func (s *loadingScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.White)
+
+	const barWidth, barHeight = 240, 4
+	x := float32(screenWidth-barWidth) / 2
+	y := float32(screenHeight-barHeight) / 2
+	progress := float32(s.loader.loaded()) / float32(s.loader.total)
+
+	vector.DrawFilledRect(screen, x, y, barWidth, barHeight, color.RGBA{220, 220, 220, 255}, false)
+	vector.DrawFilledRect(screen, x, y, barWidth*progress, barHeight, color.RGBA{52, 190, 237, 255}, false)
+
+	msg := fmt.Sprintf("Loading %d/%d", s.loader.loaded(), s.loader.total)
+	ebitenutil.DebugPrintAt(screen, msg, screenWidth/2-len(msg)*3, int(y)+12)
+}
@@ -0,0 +1,121 @@
+package hbc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// screensaverMouseSlack is how far the mouse may drift before the screensaver
+// treats it as the user coming back.
+const screensaverMouseSlack = 8
+
+// screensaverArgs recognizes the arguments Windows passes to a .scr file:
+// /s to run, /c or /c:<hwnd> to configure and /p <hwnd> to preview. Windows
+// also starts a screensaver without arguments to configure it.
+func screensaverArgs(args []string) (mode string, ok bool) {
+	if len(args) == 0 {
+		if strings.EqualFold(filepath.Ext(os.Args[0]), ".scr") {
+			return "c", true
+		}
+		return "", false
+	}
+
+	arg := strings.ToLower(args[0])
+	if !strings.HasPrefix(arg, "/") {
+		return "", false
+	}
+	mode, _, _ = strings.Cut(arg[1:], ":")
+	switch mode {
+	case "s", "c", "p":
+		return mode, true
+	}
+	return "", false
+}
+
+func runScreensaver(mode string) error {
+	switch mode {
+	case "c":
+		showScreensaverConfig()
+		return nil
+	case "p":
+		// The preview asks us to draw into a child of the given window, which
+		// ebiten cannot do. Exiting leaves the preview area blank.
+		return nil
+	}
+
+	// Windows passes no flags to a screensaver, so read the config from the
+	// per-user location if there is one.
+	common := &commonFlags{}
+	if path, err := userConfigPath(); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			common.config = path
+		}
+	}
+
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	g.debugMode = false
+	g.helpTimer = 0
+
+	ebiten.SetFullscreen(true)
+	ebiten.SetCursorMode(ebiten.CursorModeHidden)
+	return runScene(g, "go-hbc-intro", func() Scene { return &screensaverScene{game: g} })
+}
+
+// screensaverScene runs the intro until any key, click or mouse movement.
+type screensaverScene struct {
+	game           *Intro
+	started        bool
+	startX, startY int
+}
+
+func (s *screensaverScene) Update() error {
+	x, y := ebiten.CursorPosition()
+	if !s.started {
+		s.started = true
+		s.startX, s.startY = x, y
+	}
+
+	moved := abs(x-s.startX) > screensaverMouseSlack || abs(y-s.startY) > screensaverMouseSlack
+	clicked := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
+		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) ||
+		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle)
+	typed := len(inpututil.AppendJustPressedKeys(nil)) > 0
+	if moved || clicked || typed {
+		s.game.quitRequested.Store(true)
+	}
+
+	return s.game.Update()
+}
+
+func (s *screensaverScene) Draw(screen *ebiten.Image) {
+	s.game.Draw(screen)
+}
+
+func (s *screensaverScene) drawsThroughView() {}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func screensaverConfigText() string {
+	path, err := userConfigPath()
+	if err != nil {
+		path = "config.json in the user config directory"
+	}
+	return "go-hbc-intro has no settings dialog.\n\nThe screensaver reads its settings from " + path + "."
+}
+
+func printScreensaverConfig() {
+	fmt.Println(screensaverConfigText())
+}
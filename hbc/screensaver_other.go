@@ -0,0 +1,7 @@
+//go:build !windows
+
+package hbc
+
+func showScreensaverConfig() {
+	printScreensaverConfig()
+}
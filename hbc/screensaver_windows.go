@@ -0,0 +1,19 @@
+package hbc
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func showScreensaverConfig() {
+	title, _ := syscall.UTF16PtrFromString("go-hbc-intro")
+	text, _ := syscall.UTF16PtrFromString(screensaverConfigText())
+
+	const mbIconInformation = 0x40
+	messageBox := syscall.NewLazyDLL("user32.dll").NewProc("MessageBoxW")
+	if messageBox.Find() != nil {
+		printScreensaverConfig()
+		return
+	}
+	messageBox.Call(0, uintptr(unsafe.Pointer(text)), uintptr(unsafe.Pointer(title)), mbIconInformation)
+}
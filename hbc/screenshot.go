@@ -0,0 +1,71 @@
+package hbc
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Screenshot renders the current frame of the animation at the render
+// resolution, without the debug display or any other overlay. It has to be
+// called on the game loop, such as from an Effect or the embedding game's
+// Update, and returns nil until the assets have loaded.
+func (g *Intro) Screenshot() image.Image {
+	if !g.loaded {
+		return nil
+	}
+	w, h := g.renderSize()
+	canvas := ebiten.NewImage(w, h)
+	defer canvas.Deallocate()
+	g.drawWorld(canvas)
+	g.drawSeamCrossfade(canvas)
+
+	img := image.NewRGBA(canvas.Bounds())
+	canvas.ReadPixels(img.Pix)
+	return img
+}
+
+func saveScreenshot(screen *ebiten.Image) {
+	img := image.NewRGBA(screen.Bounds())
+	screen.ReadPixels(img.Pix)
+
+	name := fmt.Sprintf("hbc-%s.png", time.Now().Format("20060102-150405.000"))
+	go func() {
+		if err := writePNG(name, img); err != nil {
+			log.Printf("Warning: Could not save screenshot %s: %v\n", name, err)
+			return
+		}
+		log.Printf("Saved screenshot: %s\n", name)
+	}()
+}
+
+// copyScreenshot puts the frame on the clipboard instead of saving it.
+func copyScreenshot(screen *ebiten.Image) {
+	img := image.NewRGBA(screen.Bounds())
+	screen.ReadPixels(img.Pix)
+
+	go func() {
+		if err := copyImage(img); err != nil {
+			log.Printf("Warning: Could not copy screenshot to the clipboard: %v\n", err)
+			return
+		}
+		log.Printf("Copied screenshot to the clipboard\n")
+	}()
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
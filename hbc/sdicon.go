@@ -0,0 +1,152 @@
+package hbc
+
+import (
+	"image/color"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// The SD card icon sits in a corner of the Homebrew Channel's menu to show
+// that a card is inserted. Idle, it bobs gently and a glint runs over it
+// every few seconds.
+
+const (
+	sdIconWidth   = 26
+	sdIconHeight  = 34
+	sdIconDensity = 2
+	sdIconMargin  = 20
+	// The icon bobs by sdIconBob pixels over sdIconPeriod frames, and glints
+	// for sdGlintFrames at the start of every sdGlintPeriod.
+	sdIconBob     = 2
+	sdIconPeriod  = 150
+	sdGlintPeriod = 4 * 60
+	sdGlintFrames = 24
+	sdGlintAlpha  = 0.45
+)
+
+var (
+	sdIconBody    = color.RGBA{70, 110, 160, 255}
+	sdIconLabel   = color.RGBA{235, 240, 245, 255}
+	sdIconContact = color.RGBA{230, 190, 80, 255}
+	sdIconEdge    = color.RGBA{40, 70, 110, 255}
+)
+
+type SDIconConfig struct {
+	Enabled bool `json:"enabled"`
+	// Corner is one of "top-left", "top-right", "bottom-left" (the default)
+	// or "bottom-right".
+	Corner string `json:"corner,omitempty"`
+	// Layer is the layer it is drawn on, such as "waves" to put it behind
+	// the front waves. Defaults to "overlay".
+	Layer string `json:"layer,omitempty"`
+}
+
+type sdIcon struct {
+	img, glint *ebiten.Image
+}
+
+// sdCardPath is the card's outline with its top right corner cut off.
+func sdCardPath() *vector.Path {
+	var p vector.Path
+	p.MoveTo(2, 0)
+	p.LineTo(sdIconWidth-7, 0)
+	p.LineTo(sdIconWidth, 7)
+	p.LineTo(sdIconWidth, sdIconHeight-2)
+	p.QuadTo(sdIconWidth, sdIconHeight, sdIconWidth-2, sdIconHeight)
+	p.LineTo(2, sdIconHeight)
+	p.QuadTo(0, sdIconHeight, 0, sdIconHeight-2)
+	p.LineTo(0, 2)
+	p.QuadTo(0, 0, 2, 0)
+	p.Close()
+	return &p
+}
+
+func newSDIcon() *sdIcon {
+	var geoM ebiten.GeoM
+	geoM.Translate(1, 1)
+	geoM.Scale(sdIconDensity, sdIconDensity)
+	size := func() *ebiten.Image {
+		return ebiten.NewImage((sdIconWidth+2)*sdIconDensity, (sdIconHeight+2)*sdIconDensity)
+	}
+
+	card := sdCardPath()
+	img := size()
+	fillVerticesGeoM(img, card, nil, geoM, sdIconBody)
+	for i := range 4 {
+		var c vector.Path
+		x := float32(4 + i*4)
+		c.MoveTo(x, 2)
+		c.LineTo(x+2.5, 2)
+		c.LineTo(x+2.5, 8)
+		c.LineTo(x, 8)
+		c.Close()
+		fillVerticesGeoM(img, &c, nil, geoM, sdIconContact)
+	}
+	var label vector.Path
+	label.MoveTo(3, 13)
+	label.LineTo(sdIconWidth-3, 13)
+	label.LineTo(sdIconWidth-3, sdIconHeight-4)
+	label.LineTo(3, sdIconHeight-4)
+	label.Close()
+	fillVerticesGeoM(img, &label, nil, geoM, sdIconLabel)
+	fillVerticesGeoM(img, card, &vector.StrokeOptions{Width: 1.2, LineJoin: vector.LineJoinRound}, geoM, sdIconEdge)
+
+	glint := size()
+	fillVerticesGeoM(glint, card, nil, geoM, color.RGBA{255, 255, 255, 255})
+	return &sdIcon{img: img, glint: glint}
+}
+
+func (g *Intro) setupSDIcon() {
+	c := g.cfg.SDIcon
+	if !c.Enabled {
+		return
+	}
+	layer := LayerOverlay
+	if c.Layer != "" {
+		l, ok := layerByName(c.Layer)
+		if !ok {
+			log.Printf("Warning: Unknown layer %q for the SD card icon, using overlay\n", c.Layer)
+		} else {
+			layer = l
+		}
+	}
+	g.addLayer(layer, func(screen *ebiten.Image) {
+		g.drawSDIcon(screen, layer)
+	})
+}
+
+func (g *Intro) drawSDIcon(screen *ebiten.Image, layer Layer) {
+	if g.sdIcon == nil {
+		g.sdIcon = newSDIcon()
+	}
+	corner := g.cfg.SDIcon.Corner
+	if corner == "" {
+		corner = "bottom-left"
+	}
+	x, y := g.cornerPosition(corner, sdIconWidth, sdIconHeight, sdIconMargin)
+	if g.ticker != nil && y+sdIconHeight > g.height-tickerHeight {
+		y -= tickerHeight
+	}
+	y += math.Sin(float64(g.ticks)/sdIconPeriod*2*math.Pi) * sdIconBob * g.motionScale()
+
+	draw := func(img *ebiten.Image, alpha float64) {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-1, -1)
+		op.GeoM.Scale(1.0/sdIconDensity, 1.0/sdIconDensity)
+		op.GeoM.Translate(x, y)
+		op.ColorScale.ScaleAlpha(float32(alpha))
+		op.Filter = ebiten.FilterLinear
+		if layer.overlay() {
+			screen.DrawImage(img, op)
+		} else {
+			g.drawImage(screen, img, op)
+		}
+	}
+	draw(g.sdIcon.img, 1)
+	if t := g.ticks % sdGlintPeriod; t < sdGlintFrames && !g.reducedMotion {
+		draw(g.sdIcon.glint, sdGlintAlpha*math.Sin(math.Pi*float64(t)/sdGlintFrames))
+	}
+}
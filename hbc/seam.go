@@ -0,0 +1,127 @@
+package hbc
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// seamCrossfadeFrames is how many frames before loopEnd the crossfade
+// starts. The frames it blends in must lie after the flash.
+func (g *Intro) seamCrossfadeFrames() int {
+	if g.lowMemory || g.noShaders || g.mini.active {
+		return 0
+	}
+	return max(min(g.cfg.LoopCrossfade, loopStart-g.timeline.titleLanded()), 0)
+}
+
+// drawSeamCrossfade blends the frames leading up to loopStart over the last
+// frames before loopEnd, so that the jump back lands on a picture that is
+// already on screen even where the wave phases do not line up.
+func (g *Intro) drawSeamCrossfade(screen *ebiten.Image) {
+	n := g.seamCrossfadeFrames()
+	from := loopEnd - n
+	if n == 0 || g.count < from {
+		return
+	}
+
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	if g.seamCanvas == nil || g.seamCanvas.Bounds().Dx() != w || g.seamCanvas.Bounds().Dy() != h {
+		g.seamCanvas = ebiten.NewImage(w, h)
+	}
+	g.seamCanvas.Clear()
+
+	count := g.count
+	g.count -= loopEnd - loopStart
+	g.drawWorld(g.seamCanvas)
+	g.count = count
+
+	op := &ebiten.DrawImageOptions{}
+	op.ColorScale.ScaleAlpha(float32(g.count-from+1) / float32(n+1))
+	screen.DrawImage(g.seamCanvas, op)
+}
+
+// framesScene renders the given frames, one per Draw call, and keeps them.
+type framesScene struct {
+	game   *Intro
+	frames []int
+	images []*image.RGBA
+}
+
+func (s *framesScene) Update() error {
+	if len(s.images) >= len(s.frames) || s.game.quitRequested.Load() || ebiten.IsWindowBeingClosed() {
+		return ebiten.Termination
+	}
+	return nil
+}
+
+func (s *framesScene) Draw(screen *ebiten.Image) {
+	if len(s.images) >= len(s.frames) {
+		return
+	}
+	s.game.count = s.frames[len(s.images)]
+	s.game.Draw(screen)
+
+	img := image.NewRGBA(screen.Bounds())
+	screen.ReadPixels(img.Pix)
+	s.images = append(s.images, img)
+}
+
+func (s *framesScene) drawsThroughView() {}
+
+// frameDiff is the mean absolute difference per color channel, from 0 for
+// identical images to 255.
+func frameDiff(a, b *image.RGBA) float64 {
+	var sum int
+	for i := 0; i < len(a.Pix); i += 4 {
+		for c := 0; c < 3; c++ {
+			d := int(a.Pix[i+c]) - int(b.Pix[i+c])
+			if d < 0 {
+				d = -d
+			}
+			sum += d
+		}
+	}
+	return float64(sum) / float64(len(a.Pix)/4*3)
+}
+
+// runCheckSeam renders the frames around the jump from loopEnd back to
+// loopStart and fails if that jump changes the picture noticeably more than
+// an ordinary step between two frames on either side of it does. Unlike
+// TestLoopSeam, it looks at the pixels, waves included, so it needs a window.
+func runCheckSeam(args []string) error {
+	fset, common := newFlagSet("check-seam")
+	tolerance := fset.Float64("tolerance", 2, "allowed ratio between the seam and an ordinary frame step")
+	fset.Parse(args)
+
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	g.prepareOffline()
+
+	scene := &framesScene{game: g, frames: []int{loopEnd - 2, loopEnd - 1, loopStart, loopStart + 1}}
+	if err := runScene(g, "go-hbc-intro seam check", func() Scene { return scene }); err != nil {
+		return err
+	}
+	if len(scene.images) < len(scene.frames) {
+		return fmt.Errorf("seam check interrupted")
+	}
+
+	before := frameDiff(scene.images[0], scene.images[1])
+	seam := frameDiff(scene.images[1], scene.images[2])
+	after := frameDiff(scene.images[2], scene.images[3])
+	// The absolute slack keeps a nearly still scene from failing on
+	// rounding noise.
+	limit := *tolerance*max(before, after) + 0.5
+
+	fmt.Printf("Step before seam: %.3f\n", before)
+	fmt.Printf("Seam %d -> %d:   %.3f\n", loopEnd-1, loopStart, seam)
+	fmt.Printf("Step after seam:  %.3f\n", after)
+	if seam > limit {
+		return fmt.Errorf("loop seam differs by %.3f, more than the limit of %.3f", seam, limit)
+	}
+	fmt.Println("Loop seam is continuous.")
+	return nil
+}
@@ -0,0 +1,47 @@
+package hbc
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// visibleBubbles lists the scripted bubbles on screen at frame by type and
+// sprite, in an order that does not depend on where they are in g.bubbles.
+func visibleBubbles(g *Intro, frame int) []string {
+	var shown []string
+	for i, s := range g.simulateBubbles(frame) {
+		if s.visible {
+			shown = append(shown, fmt.Sprintf("%d %.6f %.6f %.6f %.6f", g.bubbles[i].typeID, s.x, s.y, s.rotation, s.alpha))
+		}
+	}
+	slices.Sort(shown)
+	return shown
+}
+
+// TestLoopSeam checks that the jump from the last frame of the loop back to
+// loopStart is invisible for the bubbles: the frame that would follow
+// loopEnd-1 has exactly the bubbles of loopStart.
+func TestLoopSeam(t *testing.T) {
+	for _, seed := range []int64{1, 42, 20081224} {
+		g := newTestIntro(t, seed)
+		first := visibleBubbles(g, loopStart)
+		next := visibleBubbles(g, loopEnd)
+		if len(first) == 0 {
+			t.Fatalf("seed %d: no bubbles on screen at loopStart", seed)
+		}
+		if !slices.Equal(first, next) {
+			t.Errorf("seed %d: %d bubbles on screen at loopStart, %d after the last frame; want the same", seed, len(first), len(next))
+			for _, s := range first {
+				if !slices.Contains(next, s) {
+					t.Logf("only at loopStart: %s", s)
+				}
+			}
+			for _, s := range next {
+				if !slices.Contains(first, s) {
+					t.Logf("only after the last frame: %s", s)
+				}
+			}
+		}
+	}
+}
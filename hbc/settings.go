@@ -0,0 +1,163 @@
+package hbc
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	settingsWidth     = 360
+	settingsRowHeight = 26
+	settingsFontSize  = 14
+	settingsPadding   = 16
+	settingsSliderW   = 140
+)
+
+// setting is one row of the settings panel. Sliders have a level between 0
+// and 1; toggles and choices only show their value.
+type setting struct {
+	name   string
+	value  func(g *Intro) string
+	level  func(g *Intro) float64
+	adjust func(g *Intro, dir int)
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+var settings = []setting{
+	{
+		name:  "Volume",
+		value: func(g *Intro) string { return fmt.Sprintf("%.0f%%", g.volume*100) },
+		level: func(g *Intro) float64 { return g.volume },
+		adjust: func(g *Intro, dir int) {
+			g.volume = min(max(g.volume+float64(dir)*0.1, 0), 1)
+			g.setVolume(g.baseVolume())
+		},
+	},
+	{
+		name:  "Theme",
+		value: func(g *Intro) string { return g.themeName() },
+		adjust: func(g *Intro, dir int) {
+			g.selectTheme((g.themeIndex + dir + len(themes)) % len(themes))
+		},
+	},
+	{
+		name:  "Bubble density",
+		value: func(g *Intro) string { return fmt.Sprintf("%.1fx", g.bubbleDensity) },
+		level: func(g *Intro) float64 { return g.bubbleDensity / 2 },
+		adjust: func(g *Intro, dir int) {
+			g.bubbleDensity = min(max(g.bubbleDensity+float64(dir)*0.1, 0.1), 2)
+			g.generateBubbles()
+		},
+	},
+	{
+		name:   "Reduced motion",
+		value:  func(g *Intro) string { return onOff(g.reducedMotion) },
+		adjust: func(g *Intro, dir int) { g.reducedMotion = !g.reducedMotion },
+	},
+	{
+		name:   "Loop crossfade",
+		value:  func(g *Intro) string { return onOff(g.seamCrossfadeFrames() > 0) },
+		adjust: func(g *Intro, dir int) { g.toggleCrossfade() },
+	},
+	{
+		name:  "Render scale",
+		value: func(g *Intro) string { return fmt.Sprintf("%.0f%%", g.renderScale*100) },
+		level: func(g *Intro) float64 {
+			return (g.renderScale - minRenderScale) / (maxRenderScale - minRenderScale)
+		},
+		adjust: func(g *Intro, dir int) { g.stepRenderScale(dir) },
+	},
+	{
+		name:   "Smooth scaling",
+		value:  func(g *Intro) string { return onOff(g.screenFilter) },
+		adjust: func(g *Intro, dir int) { g.screenFilter = !g.screenFilter },
+	},
+	{
+		name:   "Debug overlay",
+		value:  func(g *Intro) string { return onOff(g.debugMode) },
+		adjust: func(g *Intro, dir int) { g.debugMode = !g.debugMode },
+	},
+	{
+		name:   "Confirm quit",
+		value:  func(g *Intro) string { return onOff(g.confirmQuit) },
+		adjust: func(g *Intro, dir int) { g.confirmQuit = !g.confirmQuit },
+	},
+}
+
+// defaultCrossfade is used when the crossfade is switched on from the panel
+// without one configured.
+const defaultCrossfade = 30
+
+func (g *Intro) toggleCrossfade() {
+	if g.cfg.LoopCrossfade > 0 {
+		g.cfg.LoopCrossfade = 0
+	} else {
+		g.cfg.LoopCrossfade = defaultCrossfade
+	}
+}
+
+// updateSettings moves through the panel with the arrow keys. Left and right
+// change the selected setting; Enter does the same as right.
+func (g *Intro) updateSettings() {
+	if !g.settingsOpen {
+		return
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		g.settingsRow = (g.settingsRow + 1) % len(settings)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		g.settingsRow = (g.settingsRow + len(settings) - 1) % len(settings)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		settings[g.settingsRow].adjust(g, -1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight), inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		settings[g.settingsRow].adjust(g, 1)
+	}
+}
+
+func (g *Intro) drawSettings(ui *ebiten.Image) {
+	if !g.settingsOpen {
+		return
+	}
+
+	face := uiFace(settingsFontSize)
+	h := float64(len(settings)+1)*settingsRowHeight + settingsPadding*2
+	x := (g.width - settingsWidth) / 2
+	y := (g.height - h) / 2
+	vector.DrawFilledRect(ui, float32(x), float32(y), settingsWidth, float32(h), color.RGBA{0, 0, 0, 190}, false)
+
+	label := func(s string, lx, ly float64, c color.Color) {
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(lx, ly)
+		op.ColorScale.ScaleWithColor(c)
+		text.Draw(ui, s, face, op)
+	}
+
+	label("Settings (F2 to close)", x+settingsPadding, y+settingsPadding, color.White)
+	for i, s := range settings {
+		ry := y + settingsPadding + float64(i+1)*settingsRowHeight
+		c := color.Color(color.RGBA{180, 180, 180, 255})
+		if i == g.settingsRow {
+			c = color.RGBA{52, 190, 237, 255}
+		}
+		label(s.name, x+settingsPadding, ry, c)
+
+		vx := x + settingsWidth - settingsPadding - settingsSliderW
+		if s.level != nil {
+			bar := panelBar{x: float32(vx), y: float32(ry) + 5, w: settingsSliderW - 50}
+			bar.draw(ui, s.level(g))
+			vx += settingsSliderW - 44
+		}
+		label(s.value(g), vx, ry, c)
+	}
+}
@@ -0,0 +1,100 @@
+package hbc
+
+import (
+	"log"
+	"slices"
+)
+
+// Besides the random bubbles, the config can script bubbles of its own:
+// bursts that rise together from one spot, and emitters that release a
+// steady stream from a stretch of the bottom for a while. Frames are on the
+// timeline, from the start of the opening; x is the offset from the middle
+// of the screen in the 810x456 layout.
+
+// BurstConfig releases Count bubbles around X over Spread frames from Frame.
+type BurstConfig struct {
+	Frame  int     `json:"frame"`
+	X      float64 `json:"x"`
+	Count  int     `json:"count"`
+	Spread int     `json:"spread,omitempty"`
+}
+
+// EmitterConfig releases Rate bubbles a second between X-Width/2 and
+// X+Width/2, from frame From until To.
+type EmitterConfig struct {
+	X     float64 `json:"x"`
+	Width float64 `json:"width"`
+	From  int     `json:"from"`
+	To    int     `json:"to"`
+	Rate  float64 `json:"rate"`
+}
+
+const (
+	defaultBurstCount  = 12
+	defaultBurstSpread = 30
+	// burstWidth is how far either side of X the bubbles of a burst start.
+	burstWidth = 24
+	// maxBurstCount and maxEmitterRate keep a typo such as a rate of 100000
+	// from spawning more bubbles than can be drawn.
+	maxBurstCount  = 200
+	maxEmitterRate = 60
+)
+
+// setupSpawns clamps the bursts and emitters from the config. Emitters are
+// also cut to the frames a bubble can be on screen, since bubbles that end
+// after the loop are left out anyway.
+func (g *Intro) setupSpawns() {
+	c := &g.cfg.Bubbles
+	c.Bursts = slices.Clone(c.Bursts)
+	for i := range c.Bursts {
+		if b := &c.Bursts[i]; b.Count > maxBurstCount {
+			log.Printf("Warning: Lowering burst count %d to %d\n", b.Count, maxBurstCount)
+			b.Count = maxBurstCount
+		}
+	}
+	c.Emitters = slices.Clone(c.Emitters)
+	for i := range c.Emitters {
+		e := &c.Emitters[i]
+		if e.Rate > maxEmitterRate {
+			log.Printf("Warning: Lowering emitter rate %g to %d\n", e.Rate, maxEmitterRate)
+			e.Rate = maxEmitterRate
+		}
+		e.From, e.To = max(e.From, 0), min(e.To, loopEnd)
+	}
+}
+
+// addScriptedBubbles adds the bursts and emitters from the config. They use
+// the intro's random source, so a seed still gives the same layout.
+func (g *Intro) addScriptedBubbles() {
+	for _, b := range g.cfg.Bubbles.Bursts {
+		count, spread := b.Count, b.Spread
+		if count <= 0 {
+			count = defaultBurstCount
+		}
+		if spread <= 0 {
+			spread = defaultBurstSpread
+		}
+		for range count {
+			start := b.Frame + int(g.rng.Float64()*float64(spread))
+			g.addBubbleAt(start, b.X+(g.rng.Float64()*2-1)*burstWidth)
+		}
+	}
+	for _, e := range g.cfg.Bubbles.Emitters {
+		if e.To <= e.From || e.Rate <= 0 {
+			continue
+		}
+		count := int(float64(e.To-e.From) / 60 * e.Rate)
+		for range count {
+			start := e.From + int(g.rng.Float64()*float64(e.To-e.From))
+			g.addBubbleAt(start, e.X+(g.rng.Float64()-0.5)*e.Width)
+		}
+	}
+}
+
+// addBubbleAt adds a generated bubble that starts rising at x instead of a
+// random spot.
+func (g *Intro) addBubbleAt(start int, x float64) {
+	b := g.newBubble(start)
+	b.x, b.startX = x, x
+	g.bubbles = append(g.bubbles, b)
+}
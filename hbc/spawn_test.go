@@ -0,0 +1,20 @@
+package hbc
+
+import "testing"
+
+// TestSpawnsAreClamped checks that a typo in a burst or emitter cannot
+// spawn an unbounded number of bubbles.
+func TestSpawnsAreClamped(t *testing.T) {
+	g := newTestIntro(t, 1)
+	g.cfg.Bubbles.Bursts = []BurstConfig{{Frame: 300, Count: 1e9}}
+	g.cfg.Bubbles.Emitters = []EmitterConfig{{Width: 80, From: -1e9, To: 1e9, Rate: 1e5}}
+	g.setupSpawns()
+
+	if b := g.cfg.Bubbles.Bursts[0]; b.Count != maxBurstCount {
+		t.Errorf("burst of %d bubbles, want %d", b.Count, maxBurstCount)
+	}
+	want := EmitterConfig{Width: 80, From: 0, To: loopEnd, Rate: maxEmitterRate}
+	if e := g.cfg.Bubbles.Emitters[0]; e != want {
+		t.Errorf("emitter %+v, want %+v", e, want)
+	}
+}
@@ -0,0 +1,220 @@
+package hbc
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// spriteElement is an animated part of the intro that can be exported as a
+// sprite sheet, rendered over one cycle of its motion.
+type spriteElement struct {
+	name   string
+	width  int
+	height int
+	// One cycle lasts period frames from start.
+	start  int
+	period float64
+	draw   func(dst *ebiten.Image, frame float64)
+}
+
+// spriteElement resolves an -element flag: "title", "waves" or
+// "bubble:<type>", where the type is an index or a texture name.
+func (g *Intro) spriteElement(spec string) (spriteElement, error) {
+	w, h := g.renderSize()
+	switch spec {
+	case "title":
+		// One cycle of the current theme's title motion.
+		period := g.titleMotion.motions[g.themeIndex].period
+		return spriteElement{name: spec, width: w, height: h, start: g.timeline.title, period: period,
+			draw: func(dst *ebiten.Image, frame float64) {
+				g.count = int(math.Round(frame))
+				g.drawTitle(dst)
+			}}, nil
+	case "waves":
+		// The slowest layer sways with sin(frame/60); the others are
+		// multiples of it, close enough to loop.
+		return spriteElement{name: spec, width: w, height: h, start: loopStart, period: 120 * math.Pi,
+			draw: func(dst *ebiten.Image, frame float64) {
+				g.count = int(math.Round(frame))
+				g.drawWaves(dst)
+			}}, nil
+	}
+
+	typeName, ok := strings.CutPrefix(spec, "bubble:")
+	if !ok {
+		return spriteElement{}, fmt.Errorf("unknown element %q, want title, waves or bubble:<type>", spec)
+	}
+	id := -1
+	for i, bt := range g.bubbleTypes {
+		if bt.name == typeName || strconv.Itoa(i) == typeName {
+			id = i
+		}
+	}
+	if id < 0 {
+		return spriteElement{}, fmt.Errorf("unknown bubble type %q", typeName)
+	}
+
+	bt := g.bubbleTypes[id]
+	// Room for the bubble at any angle. Rising bubbles make half a turn in
+	// about 140 frames.
+	size := int(math.Ceil(math.Hypot(bt.width, bt.height) * g.renderScale))
+	return spriteElement{name: spec, width: size, height: size, period: 280,
+		draw: func(dst *ebiten.Image, frame float64) {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(-bt.width/2, -bt.height/2)
+			op.GeoM.Rotate(frame / 280 * 2 * math.Pi)
+			op.GeoM.Translate(float64(size)/g.renderScale/2, float64(size)/g.renderScale/2)
+			op.Filter = ebiten.FilterLinear
+			g.tint(op)
+			g.drawImage(dst, g.texture(bt.name), op)
+		}}, nil
+}
+
+// spriteSheet is written next to the sheet image. Frames are in the order
+// they play, each placed at X, Y in the sheet; Origin is where the top left
+// corner of a frame lies in the rendered scene.
+type spriteSheet struct {
+	Image       string        `json:"image"`
+	Element     string        `json:"element"`
+	FrameWidth  int           `json:"frame_width"`
+	FrameHeight int           `json:"frame_height"`
+	Columns     int           `json:"columns"`
+	FPS         float64       `json:"fps"`
+	Origin      spritePoint   `json:"origin"`
+	Frames      []spritePoint `json:"frames"`
+}
+
+type spritePoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// spriteScene renders every frame of the sheet in its first Draw, then quits.
+type spriteScene struct {
+	game   *Intro
+	elem   spriteElement
+	count  int
+	frames []*image.RGBA
+	done   bool
+}
+
+func (s *spriteScene) Update() error {
+	if s.done || s.game.quitRequested.Load() || ebiten.IsWindowBeingClosed() {
+		return ebiten.Termination
+	}
+	return nil
+}
+
+func (s *spriteScene) Draw(screen *ebiten.Image) {
+	if s.done {
+		return
+	}
+	canvas := ebiten.NewImage(s.elem.width, s.elem.height)
+	for i := range s.count {
+		canvas.Clear()
+		s.elem.draw(canvas, float64(s.elem.start)+s.elem.period*float64(i)/float64(s.count))
+		img := image.NewRGBA(canvas.Bounds())
+		canvas.ReadPixels(img.Pix)
+		s.frames = append(s.frames, img)
+	}
+	s.done = true
+}
+
+func (s *spriteScene) drawsThroughView() {}
+
+// pack crops the frames to the area any of them covers and lays them out in
+// a grid that is about square.
+func (s *spriteScene) pack() (*image.RGBA, spriteSheet) {
+	var used image.Rectangle
+	for _, f := range s.frames {
+		used = used.Union(opaqueBounds(f))
+	}
+	if used.Empty() {
+		used = image.Rect(0, 0, 1, 1)
+	}
+
+	cols := int(math.Ceil(math.Sqrt(float64(len(s.frames)))))
+	rows := (len(s.frames) + cols - 1) / cols
+	fw, fh := used.Dx(), used.Dy()
+	sheet := image.NewRGBA(image.Rect(0, 0, cols*fw, rows*fh))
+	meta := spriteSheet{
+		Element:     s.elem.name,
+		FrameWidth:  fw,
+		FrameHeight: fh,
+		Columns:     cols,
+		FPS:         60 * float64(len(s.frames)) / s.elem.period,
+		Origin:      spritePoint{used.Min.X, used.Min.Y},
+	}
+	for i, f := range s.frames {
+		at := image.Pt(i%cols*fw, i/cols*fh)
+		draw.Draw(sheet, image.Rectangle{at, at.Add(used.Size())}, f, used.Min, draw.Src)
+		meta.Frames = append(meta.Frames, spritePoint{at.X, at.Y})
+	}
+	return sheet, meta
+}
+
+func opaqueBounds(img *image.RGBA) image.Rectangle {
+	var r image.Rectangle
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.Pix[img.PixOffset(x, y)+3] != 0 {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
+
+func runSprites(args []string) error {
+	fset, common := newFlagSet("sprites")
+	element := fset.String("element", "title", "what to export: title, waves or bubble:<index or texture name>")
+	count := fset.Int("frames", 32, "number of frames over one cycle")
+	out := fset.String("o", "sprites.png", "output PNG; the metadata goes to the same name with .json")
+	fset.Parse(args)
+	if *count < 1 {
+		return fmt.Errorf("invalid frame count %d", *count)
+	}
+
+	g, err := common.newIntro()
+	if err != nil {
+		return err
+	}
+	g.prepareOffline()
+	elem, err := g.spriteElement(*element)
+	if err != nil {
+		return err
+	}
+
+	scene := &spriteScene{game: g, elem: elem, count: *count}
+	if err := runScene(g, "go-hbc-intro sprite export", func() Scene { return scene }); err != nil {
+		return err
+	}
+	if !scene.done {
+		return fmt.Errorf("export interrupted")
+	}
+
+	sheet, meta := scene.pack()
+	if err := writePNG(*out, sheet); err != nil {
+		return err
+	}
+	meta.Image = *out
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	jsonPath := strings.TrimSuffix(*out, ".png") + ".json"
+	if err := os.WriteFile(jsonPath, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d frames of %s to %s and %s\n", len(scene.frames), elem.name, *out, jsonPath)
+	return nil
+}
@@ -0,0 +1,116 @@
+package hbc
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// playbackState is what -resume needs to continue where the last run left
+// off. The seed recreates the same bubble layout.
+type playbackState struct {
+	Frame      int    `json:"frame"`
+	Loop       int    `json:"loop"`
+	Ticks      int    `json:"ticks"`
+	Seed       int64  `json:"seed"`
+	Theme      string `json:"theme"`
+	Muted      bool   `json:"muted"`
+	Debug      bool   `json:"debug"`
+	Fullscreen bool   `json:"fullscreen"`
+}
+
+func statePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-hbc-intro", "state.json"), nil
+}
+
+func loadState() (playbackState, error) {
+	var s playbackState
+	path, err := statePath()
+	if err != nil {
+		return s, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+func (g *Intro) saveState() error {
+	s := playbackState{
+		Frame:      g.count,
+		Loop:       g.loopCount,
+		Ticks:      g.ticks,
+		Seed:       g.seed,
+		Theme:      g.theme().Name,
+		Muted:      g.muted,
+		Debug:      g.debugMode,
+		Fullscreen: ebiten.IsFullscreen(),
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// restoreState applies a saved state to an intro created with its seed. The
+// players are positioned by seekAudio once the audio has loaded.
+func (g *Intro) restoreState(s playbackState) {
+	if s.Frame < 0 || s.Frame >= loopEnd {
+		return
+	}
+	g.count = s.Frame
+	g.loopCount = s.Loop
+	g.ticks = s.Ticks
+	g.setTheme(s.Theme)
+	g.muted = s.Muted
+	g.debugMode = s.Debug
+	ebiten.SetFullscreen(s.Fullscreen)
+	g.resumed = true
+}
+
+// seekAudio moves both players to where they would be had the intro played
+// from the start to the current frame. The loop music starts at the
+// timeline's loopMusic frame and keeps running across the jumps from loopEnd back to loopStart.
+func (g *Intro) seekAudio() {
+	frame := g.count + g.audioLead()
+
+	g.introStarted = true
+	g.stopAmbience()
+	g.resetClock()
+	if g.introPlayer != nil {
+		if err := g.introPlayer.SetPosition(frameTime(min(max(frame, 0), g.introFrames))); err != nil {
+			log.Printf("Warning: Could not seek intro music: %v\n", err)
+		}
+	}
+	if g.loopPlayer == nil {
+		return
+	}
+	if frame < g.timeline.loopMusic && g.loopCount == 0 {
+		// Update starts the loop music again once its frame is reached.
+		g.loopPlayer.Pause()
+		g.loopPlayer.SetPosition(0)
+		return
+	}
+	played := frame - g.timeline.loopMusic + g.loopCount*(loopEnd-loopStart)
+	if err := g.loopPlayer.SetPosition(frameTime(played)); err != nil {
+		log.Printf("Warning: Could not seek loop music: %v\n", err)
+	}
+}
@@ -0,0 +1,58 @@
+package hbc
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+// svgPath is where a vector version of a PNG texture would be.
+func svgPath(path string) string {
+	return strings.TrimSuffix(path, ".png") + ".svg"
+}
+
+// maxSVGSize is the most pixels a side of a rasterized SVG may have. It is
+// within what GPUs take and well past any texture of the scene at the
+// highest render scale.
+const maxSVGSize = 4096
+
+// decodeSVG rasterizes an SVG texture at scale times the size of its view
+// box, which stands for the texture's size in the scene.
+func decodeSVG(r io.Reader, scale float64) (image.Image, error) {
+	icon, err := oksvg.ReadIconStream(r)
+	if err != nil {
+		return nil, err
+	}
+	// Written so that a NaN size fails.
+	fw, fh := icon.ViewBox.W*scale, icon.ViewBox.H*scale
+	if !(fw > 0 && fh > 0) {
+		return nil, errors.New("empty view box")
+	}
+	if fw > maxSVGSize || fh > maxSVGSize {
+		return nil, fmt.Errorf("view box %gx%g is too large to rasterize, want up to %d pixels a side", fw, fh, maxSVGSize)
+	}
+	w, h := int(math.Ceil(fw)), int(math.Ceil(fh))
+
+	icon.SetTarget(0, 0, float64(w), float64(h))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	scanner := rasterx.NewScannerGV(w, h, img, img.Bounds())
+	icon.Draw(rasterx.NewDasher(w, h, scanner), 1)
+	return img, nil
+}
+
+// textureSize is the size a texture covers in the scene, which is smaller
+// than its pixel size for textures rasterized at a higher density.
+func (g *Intro) textureSize(img *ebiten.Image) (float64, float64) {
+	d := 1.0
+	if density, ok := g.textureDensity[img]; ok {
+		d = density
+	}
+	return float64(img.Bounds().Dx()) / d, float64(img.Bounds().Dy()) / d
+}
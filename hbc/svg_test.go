@@ -0,0 +1,25 @@
+package hbc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzSVG(f *testing.F) {
+	f.Add([]byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64"><circle cx="32" cy="32" r="30" fill="#fff" fill-opacity="0.4" stroke="#9cf"/></svg>`))
+	f.Add([]byte(`<svg viewBox="0 0 1024 64"><path d="M0 32 Q256 0 512 32 T1024 32 V64 H0 Z" fill="#4191b0"/></svg>`))
+	f.Add([]byte(`<svg viewBox="0 0 1e9 1e9"><rect width="1e9" height="1e9"/></svg>`))
+	f.Add([]byte(`<svg viewBox="0 0 0 16"></svg>`))
+	f.Add([]byte(`<svg viewBox="0 0 NaN 16"></svg>`))
+	f.Add([]byte(`<svg width="-5" height="10"><g><g><rect/></g></g></svg>`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		img, err := decodeSVG(bytes.NewReader(data), 2)
+		if err != nil {
+			return
+		}
+		if b := img.Bounds(); b.Dx() > maxSVGSize || b.Dy() > maxSVGSize || b.Empty() {
+			t.Errorf("rasterized to %dx%d, want 1 to %d pixels a side", b.Dx(), b.Dy(), maxSVGSize)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package hbc
+
+import "log"
+
+// taskbar shows how far the opening has got on the window's taskbar button
+// or dock icon, so that someone who minimized the window can tell when the
+// title has landed. It is cleared once the loop starts.
+type taskbar interface {
+	// setProgress shows p, from 0 to 1.
+	setProgress(p float64)
+	clear()
+}
+
+// taskbarSteps is how finely the progress is reported. Some platforms pay
+// for every update, so it only changes twenty times over the opening.
+const taskbarSteps = 20
+
+type taskbarProgress struct {
+	bar  taskbar
+	step int
+	done bool
+}
+
+// startTaskbarProgress sets up the progress indicator where the platform
+// has one. newTaskbar returns nil where it does not.
+func (g *Intro) startTaskbarProgress() {
+	// A resumed run or one that skips the opening has nothing to show.
+	if g.count >= loopStart {
+		return
+	}
+	bar, err := newTaskbar()
+	if err != nil {
+		log.Printf("Warning: Could not show progress on the taskbar: %v\n", err)
+		return
+	}
+	if bar != nil {
+		g.taskbar = taskbarProgress{bar: bar, step: -1}
+	}
+}
+
+func (g *Intro) updateTaskbarProgress() {
+	t := &g.taskbar
+	if t.bar == nil || t.done {
+		return
+	}
+	if g.LoopCount() > 0 || g.count >= loopStart {
+		t.bar.clear()
+		t.done = true
+		return
+	}
+	if step := g.count * taskbarSteps / loopStart; step != t.step {
+		t.step = step
+		t.bar.setProgress(float64(step) / taskbarSteps)
+	}
+}
@@ -0,0 +1,6 @@
+package hbc
+
+// Browsers give pages no taskbar or dock progress.
+func newTaskbar() (taskbar, error) {
+	return nil, nil
+}
@@ -0,0 +1,148 @@
+package hbc
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// The taskbar button's progress comes from the shell's ITaskbarList3 COM
+// object. COM wants every call on the thread that created it, so a
+// goroutine locked to its own thread owns the object and applies the latest
+// progress sent to it.
+
+type guid struct {
+	data1 uint32
+	data2 uint16
+	data3 uint16
+	data4 [8]byte
+}
+
+var (
+	clsidTaskbarList = guid{0x56fdf344, 0xfd6d, 0x11d0, [8]byte{0x95, 0x8a, 0x00, 0x60, 0x97, 0xc9, 0xa0, 0x90}}
+	iidITaskbarList3 = guid{0xea1afb91, 0x9e28, 0x4b86, [8]byte{0x90, 0xe9, 0x9e, 0x9f, 0x8a, 0x5e, 0xef, 0xaf}}
+
+	ole32 = syscall.NewLazyDLL("ole32.dll")
+
+	coInitializeEx     = ole32.NewProc("CoInitializeEx")
+	coCreateInstance   = ole32.NewProc("CoCreateInstance")
+	enumWindows        = user32.NewProc("EnumWindows")
+	getWindowThreadPID = user32.NewProc("GetWindowThreadProcessId")
+	isWindowVisible    = user32.NewProc("IsWindowVisible")
+)
+
+const (
+	coinitApartmentThreaded = 0x2
+	clsctxInprocServer      = 0x1
+
+	// Indices into ITaskbarList3's vtable, after IUnknown's three methods.
+	taskbarHrInit           = 3
+	taskbarSetProgressValue = 9
+	taskbarSetProgressState = 10
+
+	tbpfNoProgress = 0x0
+	tbpfNormal     = 0x2
+
+	// taskbarTotal is what progress is counted out of.
+	taskbarTotal = 1000
+)
+
+type windowsTaskbar struct {
+	updates chan float64
+}
+
+func newTaskbar() (taskbar, error) {
+	if err := coCreateInstance.Find(); err != nil {
+		return nil, err
+	}
+	t := &windowsTaskbar{updates: make(chan float64, 1)}
+	ready := make(chan error)
+	go t.run(ready)
+	if err := <-ready; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// send replaces any update the COM thread has not got to yet. A negative p
+// clears the progress.
+func (t *windowsTaskbar) send(p float64) {
+	select {
+	case <-t.updates:
+	default:
+	}
+	t.updates <- p
+}
+
+func (t *windowsTaskbar) setProgress(p float64) { t.send(p) }
+func (t *windowsTaskbar) clear()                { t.send(-1) }
+
+func (t *windowsTaskbar) run(ready chan<- error) {
+	runtime.LockOSThread()
+	coInitializeEx.Call(0, coinitApartmentThreaded)
+
+	// obj points at the COM object, whose first word points at its vtable.
+	var obj unsafe.Pointer
+	r, _, _ := coCreateInstance.Call(
+		uintptr(unsafe.Pointer(&clsidTaskbarList)), 0, clsctxInprocServer,
+		uintptr(unsafe.Pointer(&iidITaskbarList3)), uintptr(unsafe.Pointer(&obj)))
+	if r != 0 {
+		ready <- fmt.Errorf("CoCreateInstance failed with error %#x", r)
+		return
+	}
+	call := func(method int, args ...uintptr) uintptr {
+		vtbl := *(**[16]uintptr)(obj)
+		r, _, _ := syscall.SyscallN(vtbl[method], append([]uintptr{uintptr(obj)}, args...)...)
+		return r
+	}
+	if r := call(taskbarHrInit); r != 0 {
+		ready <- fmt.Errorf("ITaskbarList3.HrInit failed with error %#x", r)
+		return
+	}
+	ready <- nil
+
+	var hwnd uintptr
+	for p := range t.updates {
+		// The window may not exist yet when the first update arrives.
+		if hwnd == 0 {
+			if hwnd = ownWindow(); hwnd == 0 {
+				continue
+			}
+		}
+		if p < 0 {
+			call(taskbarSetProgressState, hwnd, tbpfNoProgress)
+			continue
+		}
+		call(taskbarSetProgressState, hwnd, tbpfNormal)
+		done := uint64(p * taskbarTotal)
+		if unsafe.Sizeof(uintptr(0)) == 4 {
+			// 64-bit arguments take two slots on 32-bit Windows.
+			call(taskbarSetProgressValue, hwnd, uintptr(done), uintptr(done>>32), taskbarTotal, 0)
+		} else {
+			call(taskbarSetProgressValue, hwnd, uintptr(done), taskbarTotal)
+		}
+	}
+}
+
+// ownWindow finds this process's visible top-level window, which ebiten
+// does not expose.
+func ownWindow() uintptr {
+	pid := uint32(os.Getpid())
+	var found uintptr
+	cb := syscall.NewCallback(func(hwnd, _ uintptr) uintptr {
+		var owner uint32
+		getWindowThreadPID.Call(hwnd, uintptr(unsafe.Pointer(&owner)))
+		if owner != pid {
+			return 1
+		}
+		if visible, _, _ := isWindowVisible.Call(hwnd); visible == 0 {
+			return 1
+		}
+		found = hwnd
+		return 0
+	})
+	enumWindows.Call(cb, 0)
+	return found
+}
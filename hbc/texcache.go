@@ -0,0 +1,82 @@
+package hbc
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// With lazy textures, g.textures is a cache: a texture is decoded the first
+// time it is drawn, and one that nothing in the scene refers to any more,
+// such as the bubble PNGs once drawn bubbles replace them or a texture
+// viewed in the gallery, is freed after it has gone unused for a while.
+// Large asset packs then start as soon as the music is decoded and keep only
+// what is on screen in video memory.
+
+const (
+	// textureIdleTicks is how long an unreferenced texture stays loaded
+	// after its last use. The cache is swept every textureSweepTicks.
+	textureIdleTicks  = 10 * 60
+	textureSweepTicks = 60
+)
+
+func (g *Intro) setupTextureCache() {
+	// Strict mode checks every texture up front.
+	g.lazyTextures = g.cfg.LazyTextures && !g.strict
+}
+
+// eagerTextures are the textures the asset loader decodes before the intro
+// starts.
+func (g *Intro) eagerTextures() []string {
+	if g.lazyTextures {
+		return nil
+	}
+	return g.textureList()
+}
+
+// texture returns the texture called name, decoding it first if it is not
+// loaded yet.
+func (g *Intro) texture(name string) *ebiten.Image {
+	if g.lazyTextures {
+		g.textureUse[name] = g.ticks
+	}
+	if img, ok := g.textures[name]; ok || !g.lazyTextures || !g.loaded {
+		return img
+	}
+
+	img, density, err := decodeTexture(g.assets, name, g.renderScale)
+	if err == nil {
+		return g.uploadTexture(name, img, density)
+	}
+	log.Printf("Warning: %v\n", err)
+	for _, bt := range g.bubbleTypes {
+		if bt.name == name {
+			return g.drawBubbleTexture(bt)
+		}
+	}
+	fallback := fallbackTexture(name)
+	if fallback == nil {
+		// Drawn as an empty texture rather than retried every frame.
+		fallback = ebiten.NewImage(1, 1)
+	}
+	g.textures[name] = fallback
+	return fallback
+}
+
+// sweepTextures frees the textures the scene no longer refers to that have
+// not been drawn for textureIdleTicks.
+func (g *Intro) sweepTextures() {
+	if !g.lazyTextures || g.ticks%textureSweepTicks != 0 {
+		return
+	}
+	refs := g.textureRefs()
+	for name, img := range g.textures {
+		if _, used := refs[name]; used || g.ticks-g.textureUse[name] < textureIdleTicks {
+			continue
+		}
+		img.Deallocate()
+		delete(g.textureDensity, img)
+		delete(g.textures, name)
+		delete(g.textureUse, name)
+	}
+}
@@ -0,0 +1,188 @@
+package hbc
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Theme struct {
+	Name       string
+	Background color.RGBA
+	// Tint is multiplied onto the water, bubbles and title.
+	Tint color.RGBA
+}
+
+// Palette is the colors a theme paints the intro in.
+type Palette struct {
+	Background color.RGBA
+	Tint       color.RGBA
+}
+
+func (t Theme) Palette() Palette {
+	return Palette{Background: t.Background, Tint: t.Tint}
+}
+
+var themes = []Theme{
+	{
+		Name:       "day",
+		Background: color.RGBA{255, 255, 255, 255},
+		Tint:       color.RGBA{255, 255, 255, 255},
+	},
+	{
+		Name:       "night",
+		Background: color.RGBA{18, 26, 48, 255},
+		Tint:       color.RGBA{120, 140, 200, 255},
+	},
+}
+
+// paletteFadeFrames is how long the intro takes to blend from one palette
+// to the next.
+const paletteFadeFrames = 60
+
+// paletteState is the palette on screen, fading from one to another.
+type paletteState struct {
+	from, to Palette
+	left     int
+	// custom is set while a palette from SetPalette is shown instead of the
+	// theme's own.
+	custom bool
+
+	// SetTheme and SetPalette may be called from any goroutine; the latest
+	// request is applied on the next Update.
+	mu      sync.Mutex
+	pending *paletteRequest
+}
+
+type paletteRequest struct {
+	theme   int
+	palette *Palette
+}
+
+func themeIndex(name string) (int, bool) {
+	for i, t := range themes {
+		if t.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// setTheme switches to the theme at once, for settings restored at startup.
+// An empty name keeps the current theme.
+func (g *Intro) setTheme(name string) {
+	if i, ok := themeIndex(name); ok {
+		g.themeIndex = i
+	} else if name != "" {
+		log.Printf("Warning: Unknown theme %q\n", name)
+	}
+	g.titleMotion.from = g.themeIndex
+	p := g.theme().Palette()
+	g.palette.from, g.palette.to, g.palette.left, g.palette.custom = p, p, 0, false
+}
+
+// SetTheme fades the intro to one of the built-in themes, "day" or "night".
+func (g *Intro) SetTheme(name string) error {
+	i, ok := themeIndex(name)
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+	g.palette.mu.Lock()
+	defer g.palette.mu.Unlock()
+	g.palette.pending = &paletteRequest{theme: i}
+	return nil
+}
+
+// SetPalette fades the intro to custom colors, such as an embedding
+// application's branding. The theme key or SetTheme switches back to a
+// theme.
+func (g *Intro) SetPalette(p Palette) {
+	g.palette.mu.Lock()
+	defer g.palette.mu.Unlock()
+	g.palette.pending = &paletteRequest{theme: g.themeIndex, palette: &p}
+}
+
+func (g *Intro) updatePalette() {
+	g.palette.mu.Lock()
+	req := g.palette.pending
+	g.palette.pending = nil
+	g.palette.mu.Unlock()
+
+	if req != nil {
+		if req.palette != nil {
+			g.fadeTo(*req.palette)
+			g.palette.custom = true
+		} else {
+			g.selectTheme(req.theme)
+		}
+	}
+	if g.palette.left > 0 {
+		g.palette.left--
+	}
+}
+
+// fadeTo starts blending from the colors on screen to p.
+func (g *Intro) fadeTo(p Palette) {
+	g.palette.from = g.currentPalette()
+	g.palette.to = p
+	g.palette.left = paletteFadeFrames
+	if g.reducedMotion {
+		g.palette.left = 0
+	}
+}
+
+func (g *Intro) selectTheme(i int) {
+	g.titleMotion.from = g.themeIndex
+	g.themeIndex = i
+	g.palette.custom = false
+	g.fadeTo(g.theme().Palette())
+}
+
+func (g *Intro) theme() Theme {
+	return themes[g.themeIndex]
+}
+
+// themeName is the theme shown, or "custom" for a palette from SetPalette.
+func (g *Intro) themeName() string {
+	if g.palette.custom {
+		return "custom"
+	}
+	return g.theme().Name
+}
+
+func (g *Intro) cycleTheme() {
+	g.selectTheme((g.themeIndex + 1) % len(themes))
+}
+
+// currentPalette is the palette on screen, part way through a fade.
+func (g *Intro) currentPalette() Palette {
+	s := &g.palette
+	if s.left == 0 {
+		return s.to
+	}
+	t := s.progress()
+	return Palette{
+		Background: lerpRGBA(s.from.Background, s.to.Background, t),
+		Tint:       lerpRGBA(s.from.Tint, s.to.Tint, t),
+	}
+}
+
+// progress is how far the fade has got, from 0 to 1, easing in and out.
+func (s *paletteState) progress() float64 {
+	t := 1 - float64(s.left)/paletteFadeFrames
+	return t * t * (3 - 2*t)
+}
+
+func lerpRGBA(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t + 0.5)
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), mix(a.A, b.A)}
+}
+
+func (g *Intro) tint(op *ebiten.DrawImageOptions) {
+	op.ColorScale.ScaleWithColor(g.currentPalette().Tint)
+}
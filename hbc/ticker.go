@@ -0,0 +1,134 @@
+package hbc
+
+import (
+	"image/color"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	tickerHeight    = 28
+	tickerFontSize  = 15
+	tickerSeparator = "   •   "
+)
+
+type TickerConfig struct {
+	Lines []string `json:"lines,omitempty"`
+	// File is a text file with one line per entry. It is read again every
+	// Reload seconds, so another program can keep it up to date.
+	File   string  `json:"file,omitempty"`
+	Reload int     `json:"reload,omitempty"`
+	Speed  float64 `json:"speed,omitempty"`
+}
+
+type ticker struct {
+	text   string
+	width  float64
+	offset float64
+	lines  chan []string
+}
+
+func (g *Intro) startTicker(cfg TickerConfig) {
+	if len(cfg.Lines) == 0 && cfg.File == "" {
+		return
+	}
+	g.ticker = &ticker{lines: make(chan []string, 1)}
+	g.ticker.setLines(cfg.Lines)
+	if cfg.File == "" {
+		return
+	}
+
+	reload := 30 * time.Second
+	if cfg.Reload > 0 {
+		reload = time.Duration(cfg.Reload) * time.Second
+	}
+	go func() {
+		for {
+			lines, err := readTickerFile(cfg.File)
+			if err != nil {
+				log.Printf("Warning: Could not read ticker file: %v\n", err)
+			} else {
+				g.ticker.lines <- lines
+			}
+			time.Sleep(reload)
+		}
+	}()
+}
+
+func readTickerFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+func (t *ticker) setLines(lines []string) {
+	text := strings.Join(lines, tickerSeparator)
+	if text != "" {
+		text += tickerSeparator
+	}
+	if text == t.text {
+		return
+	}
+	t.text = text
+	t.width = 0
+	t.offset = 0
+}
+
+func (g *Intro) updateTicker() {
+	t := g.ticker
+	if t == nil {
+		return
+	}
+	select {
+	case lines := <-t.lines:
+		t.setLines(lines)
+	default:
+	}
+
+	speed := g.cfg.Ticker.Speed
+	if speed <= 0 {
+		speed = 60
+	}
+	t.offset += speed / 60
+	if t.width > 0 && t.offset >= t.width {
+		t.offset -= t.width
+	}
+}
+
+func (g *Intro) drawTicker(ui *ebiten.Image) {
+	t := g.ticker
+	if t == nil || t.text == "" {
+		return
+	}
+
+	face := uiFace(tickerFontSize)
+	if t.width == 0 {
+		t.width, _ = text.Measure(t.text, face, 0)
+	}
+
+	top := float32(g.height - tickerHeight)
+	vector.DrawFilledRect(ui, 0, top, float32(g.width), tickerHeight, color.RGBA{0, 0, 0, 160}, false)
+
+	// Draw the text as many times as it takes to fill the strip, so it
+	// wraps around without a gap.
+	y := float64(top) + (tickerHeight-face.Metrics().HAscent-face.Metrics().HDescent)/2
+	for x := -t.offset; x < g.width; x += t.width {
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(x, y)
+		text.Draw(ui, t.text, face, op)
+	}
+}
@@ -0,0 +1,62 @@
+package hbc
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Tilting a phone shifts the wave layers against each other, the nearer ones
+// further, so the scene looks like a diorama behind the screen.
+
+const (
+	// A tilt of tiltRange radians or more shifts the nearest layer by
+	// parallaxX and parallaxY pixels.
+	tiltRange = math.Pi / 6
+	parallaxX = 24
+	parallaxY = 8
+	// tiltSmoothing is how much of the way to the sensor reading the
+	// offset moves each tick, which hides the sensor's jitter.
+	tiltSmoothing = 0.1
+)
+
+// tilt is the latest sensor reading, in radians from holding the device
+// upright: x is the roll to the right, y the pitch away from the viewer.
+// Sensors report on their own goroutine, so both are stored as float bits.
+var tilt struct {
+	x, y atomic.Uint64
+	seen atomic.Bool
+}
+
+// SetTilt reports how far the device is tilted, in radians. Browser builds
+// read the device orientation themselves; an app embedding the intro on
+// Android or iOS passes its gyroscope or accelerometer readings here.
+func SetTilt(x, y float64) {
+	tilt.x.Store(math.Float64bits(x))
+	tilt.y.Store(math.Float64bits(y))
+	tilt.seen.Store(true)
+}
+
+type parallax struct {
+	x, y float64
+}
+
+func (g *Intro) updateParallax() {
+	if !tilt.seen.Load() {
+		return
+	}
+	scale := 1.0
+	if g.reducedMotion {
+		scale = 0
+	}
+	clamp := func(v float64) float64 { return min(max(v/tiltRange, -1), 1) * scale }
+	tx := clamp(math.Float64frombits(tilt.x.Load()))
+	ty := clamp(math.Float64frombits(tilt.y.Load()))
+	g.parallax.x += (tx - g.parallax.x) * tiltSmoothing
+	g.parallax.y += (ty - g.parallax.y) * tiltSmoothing
+}
+
+// parallaxOffset is how far the wave layer at depth, from 0 for the back to
+// 1 for the front, is shifted.
+func (g *Intro) parallaxOffset(depth float64) (float64, float64) {
+	return g.parallax.x * parallaxX * depth, g.parallax.y * parallaxY * depth
+}
@@ -0,0 +1,26 @@
+package hbc
+
+import (
+	"math"
+	"syscall/js"
+)
+
+// startTiltSensor listens for the browser's device orientation, which
+// phones and tablets report from their gyroscope. Desktop browsers never
+// send the event, so the scene stays still there.
+func startTiltSensor() {
+	window := js.Global()
+	if window.Get("DeviceOrientationEvent").IsUndefined() {
+		return
+	}
+	window.Call("addEventListener", "deviceorientation", js.FuncOf(func(this js.Value, args []js.Value) any {
+		e := args[0]
+		gamma, beta := e.Get("gamma"), e.Get("beta")
+		if gamma.IsNull() || beta.IsNull() {
+			return nil
+		}
+		// beta is 90 degrees with the phone held upright.
+		SetTilt(gamma.Float()*math.Pi/180, (beta.Float()-90)*math.Pi/180)
+		return nil
+	}))
+}
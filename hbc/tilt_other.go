@@ -0,0 +1,7 @@
+//go:build !js
+
+package hbc
+
+// startTiltSensor does nothing on desktops. Native mobile apps feed their
+// sensor readings to SetTilt.
+func startTiltSensor() {}
@@ -0,0 +1,73 @@
+package hbc
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// The timecode burns the frame number into a corner of the window, so a
+// screen capture can be lined up frame by frame with footage of the real
+// Homebrew Channel. Frames count from the start of the opening at 60 per
+// second and keep counting through the loops, as in a capture started with
+// the channel.
+const (
+	timecodeSize    = 11
+	timecodeMargin  = 8
+	timecodePadding = 4
+	timecodeAlpha   = 0.75
+)
+
+var (
+	timecodeColor = color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+	timecodeBack  = color.RGBA{0x00, 0x00, 0x00, 0x60}
+)
+
+type TimecodeConfig struct {
+	Enabled bool `json:"enabled"`
+	// Corner is one of "top-left", "top-right" (the default),
+	// "bottom-left" or "bottom-right".
+	Corner string `json:"corner,omitempty"`
+}
+
+// elapsedFrames is how many frames have played since the opening began.
+func (g *Intro) elapsedFrames() int {
+	return g.count + g.loopCount*(loopEnd-loopStart)
+}
+
+// timecode formats frames as hours:minutes:seconds:frames at 60 per second.
+func timecode(frames int) string {
+	ff := frames % 60
+	s := frames / 60
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", s/3600, s/60%60, s%60, ff)
+}
+
+func (g *Intro) drawTimecode(ui *ebiten.Image) {
+	if !g.cfg.Timecode.Enabled {
+		return
+	}
+	frames := g.elapsedFrames()
+	label := fmt.Sprintf("%06d  %s", frames, timecode(frames))
+
+	face := monoFace(timecodeSize)
+	tw, th := text.Measure(label, face, 0)
+	w, h := tw+2*timecodePadding, th+2*timecodePadding
+
+	corner := g.cfg.Timecode.Corner
+	if corner == "" {
+		corner = "top-right"
+	}
+	x, y := g.cornerPosition(corner, w, h, timecodeMargin)
+	if g.ticker != nil && y+h > g.height-tickerHeight {
+		y -= tickerHeight
+	}
+
+	drawRoundedRect(ui, float32(x), float32(y), float32(w), float32(h), 3, timecodeBack, color.RGBA{})
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x+timecodePadding, y+timecodePadding)
+	op.ColorScale.ScaleWithColor(timecodeColor)
+	op.ColorScale.ScaleAlpha(timecodeAlpha)
+	text.Draw(ui, label, face, op)
+}
@@ -0,0 +1,97 @@
+package hbc
+
+import (
+	"log"
+	"math"
+)
+
+// TimelineConfig moves the beats of the opening, in frames at 60 a second
+// from its start. Settings left out keep the original timing, which is
+// matched to the music: the flash lands on the boom at frame 248. Every
+// beat has to come before the loop starts at frame 360, and so does the
+// flash clearing; a timeline where the title lands later is ignored.
+type TimelineConfig struct {
+	// WavesRise is how long the water takes to rise into place.
+	WavesRise int `json:"waves_rise,omitempty"`
+	// Bubbles is the frame the first bubbles start rising.
+	Bubbles int `json:"bubbles,omitempty"`
+	// Title is the frame the title appears and starts to bob.
+	Title int `json:"title,omitempty"`
+	// Flash is the frame the white flash is brightest, and FlashFade how
+	// many frames it takes to clear.
+	Flash     int `json:"flash,omitempty"`
+	FlashFade int `json:"flash_fade,omitempty"`
+	// LoopMusic is the frame the loop music starts.
+	LoopMusic int `json:"loop_music,omitempty"`
+}
+
+// timeline is a TimelineConfig with the defaults filled in.
+type timeline struct {
+	wavesRise, bubbles, title, flash, flashFade, loopMusic int
+}
+
+var defaultTimeline = timeline{
+	wavesRise: 244,
+	bubbles:   140,
+	title:     startBoom,
+	flash:     startBoom,
+	flashFade: flashFadeFrames,
+	loopMusic: startBoom,
+}
+
+func (c TimelineConfig) resolve() timeline {
+	t := defaultTimeline
+	beat := func(dst *int, v int) {
+		if v > 0 {
+			*dst = min(v, loopStart-1)
+		}
+	}
+	beat(&t.wavesRise, c.WavesRise)
+	beat(&t.bubbles, c.Bubbles)
+	beat(&t.title, c.Title)
+	beat(&t.flash, c.Flash)
+	beat(&t.loopMusic, c.LoopMusic)
+	if c.FlashFade > 0 {
+		t.flashFade = c.FlashFade
+	}
+	// Milestones on the frame the title lands would be sent again on every
+	// loop if it were part of the loop.
+	if t.titleLanded() >= loopStart {
+		log.Printf("Warning: Ignoring timeline, the title lands on frame %d, want before %d\n", t.titleLanded(), loopStart)
+		return defaultTimeline
+	}
+	return t
+}
+
+// config is the timeline as it would be written back to a config file,
+// leaving out the beats that keep their original timing.
+func (t timeline) config() TimelineConfig {
+	keep := func(v, def int) int {
+		if v == def {
+			return 0
+		}
+		return v
+	}
+	d := defaultTimeline
+	return TimelineConfig{
+		WavesRise: keep(t.wavesRise, d.wavesRise),
+		Bubbles:   keep(t.bubbles, d.bubbles),
+		Title:     keep(t.title, d.title),
+		Flash:     keep(t.flash, d.flash),
+		FlashFade: keep(t.flashFade, d.flashFade),
+		LoopMusic: keep(t.loopMusic, d.loopMusic),
+	}
+}
+
+// titleLanded is the frame the flash has cleared and the title is fully
+// visible.
+func (t timeline) titleLanded() int {
+	return t.flash + t.flashFade
+}
+
+// riseProgress is how far the water has risen at frame, easing out as it
+// settles.
+func (t timeline) riseProgress(frame int) float64 {
+	p := min(float64(frame)/float64(t.wavesRise), 1.0)
+	return math.Sin(p * math.Pi / 2)
+}
@@ -0,0 +1,20 @@
+package hbc
+
+import "testing"
+
+// TestTitleLandsBeforeLoop checks that a timeline where the title would land
+// on the loop or later is ignored rather than cut short.
+func TestTitleLandsBeforeLoop(t *testing.T) {
+	for _, c := range []TimelineConfig{
+		{Flash: loopStart - 10, FlashFade: 10},
+		{Flash: loopStart + 50},
+	} {
+		if got := c.resolve(); got != defaultTimeline {
+			t.Errorf("%+v resolved to %+v, want the default timeline", c, got)
+		}
+	}
+	c := TimelineConfig{Flash: loopStart - 10, FlashFade: 9}
+	if got := c.resolve().titleLanded(); got != loopStart-1 {
+		t.Errorf("%+v lands the title on %d, want %d", c, got, loopStart-1)
+	}
+}
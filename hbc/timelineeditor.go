@@ -0,0 +1,329 @@
+package hbc
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// The timeline editor shows the beats of the opening as keyframes on one
+// track each, under a preview of the scene. Dragging a keyframe moves its
+// beat and the preview follows at once; S writes the timeline back to the
+// config file.
+const (
+	timelineRowHeight  = 16
+	timelineLabelWidth = 80
+	timelineKeySize    = 5
+	// timelinePreviewEnd is where playing the preview wraps back to the
+	// start, a second into the loop.
+	timelinePreviewEnd = loopStart + 60
+)
+
+var (
+	timelineKeyColor      = color.RGBA{52, 190, 237, 255}
+	timelineSelectedColor = color.RGBA{255, 210, 60, 255}
+	timelineSpanColor     = color.RGBA{52, 190, 237, 90}
+)
+
+// timelineKey is one draggable beat. set receives the frame the key was
+// dragged to.
+type timelineKey struct {
+	track int
+	label string
+	frame func(t timeline) int
+	set   func(t *timeline, frame int)
+	// span draws a bar from this frame to the key.
+	span func(t timeline) int
+}
+
+var timelineTracks = []string{"waves", "bubbles", "title", "flash", "audio"}
+
+var timelineKeys = []timelineKey{
+	{
+		track: 0, label: "water settled",
+		frame: func(t timeline) int { return t.wavesRise },
+		set:   func(t *timeline, f int) { t.wavesRise = max(f, 1) },
+		span:  func(t timeline) int { return 0 },
+	},
+	{
+		track: 1, label: "first bubbles",
+		frame: func(t timeline) int { return t.bubbles },
+		set:   func(t *timeline, f int) { t.bubbles = f },
+	},
+	{
+		track: 2, label: "title",
+		frame: func(t timeline) int { return t.title },
+		set:   func(t *timeline, f int) { t.title = max(f, 1) },
+	},
+	{
+		track: 3, label: "flash peak",
+		frame: func(t timeline) int { return t.flash },
+		set: func(t *timeline, f int) {
+			t.flash = min(max(f, 1), loopStart-2)
+			t.flashFade = min(t.flashFade, loopStart-1-t.flash)
+		},
+	},
+	{
+		track: 3, label: "flash cleared",
+		frame: func(t timeline) int { return t.titleLanded() },
+		set:   func(t *timeline, f int) { t.flashFade = min(max(f-t.flash, 1), loopStart-1-t.flash) },
+		span:  func(t timeline) int { return t.flash },
+	},
+	{
+		track: 4, label: "loop music",
+		frame: func(t timeline) int { return t.loopMusic },
+		set:   func(t *timeline, f int) { t.loopMusic = max(f, 1) },
+	},
+}
+
+type timelineEditor struct {
+	game *Intro
+	path string
+
+	frame   int
+	playing bool
+	// selected is the index of the last key clicked, or -1.
+	selected  int
+	dragging  bool
+	scrubbing bool
+
+	dirty         bool
+	quitArmed     bool
+	message       string
+	messageFrames int
+}
+
+func newTimelineEditor(g *Intro, path string) *timelineEditor {
+	g.prepareOffline()
+	return &timelineEditor{game: g, path: path, selected: -1}
+}
+
+func (e *timelineEditor) drawsThroughView() {}
+
+// trackRect is the area of the tracks, below the preview.
+func (e *timelineEditor) trackRect() (x, y, w, h float64) {
+	g := e.game
+	h = float64(len(timelineTracks) * timelineRowHeight)
+	x = editorMargin + timelineLabelWidth
+	return x, g.height - editorMargin - h, g.width - editorMargin - x, h
+}
+
+func (e *timelineEditor) frameX(frame int) float64 {
+	x, _, w, _ := e.trackRect()
+	return x + float64(frame)/loopStart*w
+}
+
+func (e *timelineEditor) frameAt(x float64) int {
+	tx, _, tw, _ := e.trackRect()
+	return min(max(int(math.Round((x-tx)/tw*loopStart)), 0), loopStart-1)
+}
+
+func (e *timelineEditor) keyPosition(i int) (float64, float64) {
+	_, y, _, _ := e.trackRect()
+	k := timelineKeys[i]
+	return e.frameX(k.frame(e.game.timeline)), y + (float64(k.track)+0.5)*timelineRowHeight
+}
+
+// keyAt is the key under the cursor, or -1.
+func (e *timelineEditor) keyAt(x, y float64) int {
+	for i := range timelineKeys {
+		kx, ky := e.keyPosition(i)
+		if math.Abs(x-kx) <= timelineKeySize+2 && math.Abs(y-ky) <= timelineRowHeight/2 {
+			return i
+		}
+	}
+	return -1
+}
+
+func (e *timelineEditor) say(format string, args ...any) {
+	e.message = fmt.Sprintf(format, args...)
+	e.messageFrames = editorMessageFrames
+}
+
+func (e *timelineEditor) Update() error {
+	g := e.game
+	if g.quitRequested.Load() || ebiten.IsWindowBeingClosed() {
+		return ebiten.Termination
+	}
+	if g.input.JustPressed(ActionQuit) {
+		if !e.dirty || e.quitArmed {
+			return ebiten.Termination
+		}
+		e.quitArmed = true
+		e.say("Unsaved changes: S saves, Esc again quits")
+	}
+
+	e.updateKeys()
+	e.updateMouse()
+
+	if e.playing {
+		if e.frame++; e.frame >= timelinePreviewEnd {
+			e.frame = 0
+		}
+	}
+	g.count = e.frame
+	g.ticks++
+	if e.messageFrames > 0 {
+		e.messageFrames--
+	}
+	return nil
+}
+
+func (e *timelineEditor) updateKeys() {
+	step := 1
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		step = 10
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		e.playing = !e.playing
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight):
+		e.playing = false
+		e.frame = min(e.frame+step, timelinePreviewEnd-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		e.playing = false
+		e.frame = max(e.frame-step, 0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) && e.selected >= 0:
+		e.moveKey(e.selected, min(e.frame, loopStart-1))
+	case inpututil.IsKeyJustPressed(ebiten.KeyR) && e.selected >= 0:
+		e.moveKey(e.selected, timelineKeys[e.selected].frame(defaultTimeline))
+	case inpututil.IsKeyJustPressed(ebiten.KeyS):
+		e.save()
+	}
+}
+
+func (e *timelineEditor) updateMouse() {
+	g := e.game
+	x, y := g.cursorPosition()
+	_, ty, _, _ := e.trackRect()
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && y >= ty-editorMargin {
+		e.playing = false
+		if k := e.keyAt(x, y); k >= 0 {
+			e.selected, e.dragging = k, true
+		} else {
+			e.scrubbing = true
+		}
+	}
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		if e.dragging && timelineKeys[e.selected].track == 1 {
+			// The bubbles are laid out once, so only regenerate them when
+			// the drag ends.
+			g.regenerateBubbles()
+		}
+		e.dragging, e.scrubbing = false, false
+	}
+	switch {
+	case e.dragging:
+		e.moveKey(e.selected, e.frameAt(x))
+		e.frame = timelineKeys[e.selected].frame(g.timeline)
+	case e.scrubbing:
+		e.frame = e.frameAt(x)
+	}
+}
+
+func (e *timelineEditor) moveKey(i, frame int) {
+	g := e.game
+	t := g.timeline
+	timelineKeys[i].set(&t, frame)
+	// Going through the config applies the same limits as loading it.
+	t = t.config().resolve()
+	if t == g.timeline {
+		return
+	}
+	g.timeline = t
+	e.dirty = true
+	e.quitArmed = false
+	if !e.dragging && timelineKeys[i].track == 1 {
+		g.regenerateBubbles()
+	}
+}
+
+func (e *timelineEditor) save() {
+	err := updateConfigFile(e.path, func(doc map[string]json.RawMessage) error {
+		c := e.game.timeline.config()
+		if c == (TimelineConfig{}) {
+			delete(doc, "timeline")
+			return nil
+		}
+		return setConfigKey(doc, "timeline", c)
+	})
+	if err != nil {
+		e.say("Could not save %s: %v", e.path, err)
+		return
+	}
+	e.dirty = false
+	e.say("Saved the timeline to %s", e.path)
+}
+
+func (e *timelineEditor) Draw(screen *ebiten.Image) {
+	g := e.game
+	g.drawWorld(screen)
+
+	ui := g.uiLayer(screen)
+	e.drawTracks(ui)
+	e.drawStatus(ui)
+	g.flushUILayer(screen, ui)
+}
+
+func (e *timelineEditor) drawTracks(ui *ebiten.Image) {
+	g := e.game
+	x, y, w, h := e.trackRect()
+	vector.DrawFilledRect(ui, editorMargin, float32(y), float32(x+w-editorMargin), float32(h), editorTrackColor, false)
+	for i, name := range timelineTracks {
+		ry := y + float64(i*timelineRowHeight)
+		if i > 0 {
+			vector.StrokeLine(ui, float32(x), float32(ry), float32(x+w), float32(ry), 1, editorLoopColor, false)
+		}
+		ebitenutil.DebugPrintAt(ui, name, editorMargin+4, int(ry))
+	}
+
+	for i, k := range timelineKeys {
+		kx, ky := e.keyPosition(i)
+		if k.span != nil {
+			sx := e.frameX(k.span(g.timeline))
+			vector.DrawFilledRect(ui, float32(sx), float32(ky-2), float32(kx-sx), 4, timelineSpanColor, false)
+		}
+		c := timelineKeyColor
+		if i == e.selected {
+			c = timelineSelectedColor
+		}
+		var p vector.Path
+		p.MoveTo(float32(kx), float32(ky-timelineKeySize))
+		p.LineTo(float32(kx+timelineKeySize), float32(ky))
+		p.LineTo(float32(kx), float32(ky+timelineKeySize))
+		p.LineTo(float32(kx-timelineKeySize), float32(ky))
+		p.Close()
+		fillVertices(ui, &p, nil, c)
+	}
+
+	if e.frame < loopStart {
+		px := float32(e.frameX(e.frame))
+		vector.StrokeLine(ui, px, float32(y)-4, px, float32(y+h)+4, 2, color.White, false)
+	}
+}
+
+func (e *timelineEditor) drawStatus(ui *ebiten.Image) {
+	t := e.game.timeline
+	key := "Click a keyframe to select it"
+	if e.selected >= 0 {
+		k := timelineKeys[e.selected]
+		key = fmt.Sprintf("%s: frame %d", k.label, k.frame(t))
+	}
+	saved := ""
+	if e.dirty {
+		saved = "  (unsaved)"
+	}
+	status := fmt.Sprintf("Frame %d  %s%s\nDrag keyframes to move them  Enter: move to playhead  R: reset\nSpace: play  Left/Right: step  S: save to %s  Esc: quit",
+		e.frame, key, saved, e.path)
+	if e.messageFrames > 0 {
+		status += "\n\n" + e.message
+	}
+	ebitenutil.DebugPrintAt(ui, status, 8, 8)
+}
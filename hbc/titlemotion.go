@@ -0,0 +1,105 @@
+package hbc
+
+import (
+	"log"
+	"math"
+)
+
+// TitleMotionConfig sets how the title moves once it has appeared. Fields
+// left out take the pattern's defaults.
+type TitleMotionConfig struct {
+	// Pattern is "bob", up and down, "figure-eight", a sideways eight, or
+	// "rotate", rocking about its middle.
+	Pattern string `json:"pattern,omitempty"`
+	// Amplitude is how far the title moves: pixels for bob and
+	// figure-eight, degrees for rotate.
+	Amplitude float64 `json:"amplitude,omitempty"`
+	// Period is how many frames one cycle takes, at 60 a second.
+	Period float64 `json:"period,omitempty"`
+}
+
+type titleMotion struct {
+	pattern   string
+	amplitude float64
+	period    float64
+}
+
+// titlePatterns holds each pattern with its defaults. The bob is the
+// original motion, sin(frame/25) ten pixels either way.
+var titlePatterns = map[string]titleMotion{
+	"bob":          {pattern: "bob", amplitude: 10, period: 50 * math.Pi},
+	"figure-eight": {pattern: "figure-eight", amplitude: 12, period: 100 * math.Pi},
+	"rotate":       {pattern: "rotate", amplitude: 3, period: 100 * math.Pi},
+}
+
+// titlePose is where the title is moved to from its resting place.
+type titlePose struct {
+	x, y  float64
+	angle float64
+}
+
+func (m titleMotion) pose(frame float64) titlePose {
+	phase := frame / m.period * 2 * math.Pi
+	switch m.pattern {
+	case "figure-eight":
+		return titlePose{x: m.amplitude * math.Sin(phase), y: m.amplitude / 2 * math.Sin(2*phase)}
+	case "rotate":
+		return titlePose{angle: m.amplitude * math.Pi / 180 * math.Sin(phase)}
+	}
+	return titlePose{y: m.amplitude * math.Sin(phase)}
+}
+
+// titleMotionState is the motion of each theme, and the theme whose motion
+// the title is blending away from while the palette fades.
+type titleMotionState struct {
+	motions []titleMotion
+	from    int
+}
+
+// setupTitleMotion resolves the title_motion setting of each theme.
+func (g *Intro) setupTitleMotion() {
+	s := &g.titleMotion
+	s.motions = make([]titleMotion, len(themes))
+	for i := range s.motions {
+		s.motions[i] = titlePatterns["bob"]
+	}
+	for name, c := range g.cfg.TitleMotion {
+		i, ok := themeIndex(name)
+		if !ok {
+			log.Printf("Warning: Ignoring title motion for unknown theme %q\n", name)
+			continue
+		}
+		pattern := c.Pattern
+		if pattern == "" {
+			pattern = "bob"
+		}
+		m, ok := titlePatterns[pattern]
+		if !ok {
+			log.Printf("Warning: Unknown title motion %q, using bob\n", c.Pattern)
+			m = titlePatterns["bob"]
+		}
+		if c.Amplitude > 0 {
+			m.amplitude = c.Amplitude
+		}
+		if c.Period > 0 {
+			m.period = c.Period
+		}
+		s.motions[i] = m
+	}
+	s.from = g.themeIndex
+}
+
+// titlePose is the title's pose at frame. While the palette fades between
+// themes, the title blends from one theme's motion to the other's.
+func (g *Intro) titlePose(frame float64) titlePose {
+	s := &g.titleMotion
+	scale := g.motionScale()
+	to := s.motions[g.themeIndex].pose(frame)
+	t := g.palette.progress()
+	if t < 1 && s.from != g.themeIndex {
+		from := s.motions[s.from].pose(frame)
+		lerp := func(a, b float64) float64 { return (a + (b-a)*t) * scale }
+		return titlePose{x: lerp(from.x, to.x), y: lerp(from.y, to.y), angle: lerp(from.angle, to.angle)}
+	}
+	return titlePose{x: to.x * scale, y: to.y * scale, angle: to.angle * scale}
+}
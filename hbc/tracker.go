@@ -0,0 +1,410 @@
+package hbc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Tracker modules are rendered to PCM once when loaded, like FLAC files.
+// Loop points come from the pattern order: rendering stops when the song
+// jumps back to a row it has already played, or runs off the end of the
+// order list and returns to the restart position, and the music loops from
+// there.
+
+const (
+	// paulaClock is the Amiga's PAL sample clock; a note plays at
+	// paulaClock/period samples per second.
+	paulaClock = 3546894.6
+	// moduleMaxLength stops songs that never loop.
+	moduleMaxLength = 10 * 60 * sampleRate * 4
+)
+
+// errXM is returned for FastTracker 2 modules, which are not played; only
+// ProTracker-style MOD files are.
+var errXM = errors.New("XM modules are not supported, convert the module to MOD, WAV or FLAC")
+
+type modSample struct {
+	data      []int8
+	finetune  int
+	volume    int
+	loopStart int
+	loopLen   int
+}
+
+type modNote struct {
+	sample int
+	period int
+	effect int
+	param  int
+}
+
+type module struct {
+	title    string
+	channels int
+	samples  [31]modSample
+	orders   []int
+	restart  int
+	patterns [][]modNote // 64 rows of channels notes each
+}
+
+// parseModule reads a ProTracker-style MOD file with 31 samples. The number
+// of channels comes from the tag, e.g. "M.K." for four or "8CHN" for eight.
+func parseModule(data []byte) (*module, error) {
+	if len(data) < 1084 {
+		return nil, errors.New("file too short for a module")
+	}
+	if bytes.HasPrefix(data, []byte("Extended Module: ")) {
+		return nil, errXM
+	}
+	m := &module{title: strings.TrimRight(string(data[:20]), "\x00 ")}
+
+	tag := string(data[1080:1084])
+	switch {
+	case tag == "M.K." || tag == "M!K!" || tag == "FLT4" || tag == "4CHN":
+		m.channels = 4
+	case strings.HasSuffix(tag, "CHN") && tag[0] >= '1' && tag[0] <= '9':
+		m.channels = int(tag[0] - '0')
+	case strings.HasSuffix(tag, "CH") && tag[0] >= '1' && tag[0] <= '3' && tag[1] >= '0' && tag[1] <= '9':
+		m.channels = int(tag[0]-'0')*10 + int(tag[1]-'0')
+	default:
+		return nil, fmt.Errorf("unsupported module type %q", tag)
+	}
+
+	for i := range m.samples {
+		h := data[20+30*i : 50+30*i]
+		m.samples[i] = modSample{
+			data:      make([]int8, int(binary.BigEndian.Uint16(h[22:]))*2),
+			finetune:  int(int8(h[24]<<4) >> 4),
+			volume:    min(int(h[25]), 64),
+			loopStart: int(binary.BigEndian.Uint16(h[26:])) * 2,
+			loopLen:   int(binary.BigEndian.Uint16(h[28:])) * 2,
+		}
+	}
+
+	songLength := int(data[950])
+	if songLength == 0 || songLength > 128 {
+		return nil, fmt.Errorf("invalid song length %d", songLength)
+	}
+	patterns := 0
+	for _, o := range data[952:1080] {
+		patterns = max(patterns, int(o)+1)
+	}
+	for _, o := range data[952 : 952+songLength] {
+		m.orders = append(m.orders, int(o))
+	}
+	if restart := int(data[951]); restart < songLength {
+		m.restart = restart
+	}
+
+	r := bytes.NewReader(data[1084:])
+	cell := make([]byte, 4)
+	for range patterns {
+		p := make([]modNote, 64*m.channels)
+		for i := range p {
+			if _, err := io.ReadFull(r, cell); err != nil {
+				return nil, fmt.Errorf("pattern data: %w", err)
+			}
+			p[i] = modNote{
+				sample: int(cell[0]&0xf0 | cell[2]>>4),
+				period: int(cell[0]&0x0f)<<8 | int(cell[1]),
+				effect: int(cell[2] & 0x0f),
+				param:  int(cell[3]),
+			}
+		}
+		m.patterns = append(m.patterns, p)
+	}
+
+	// Sample data may be cut short in ripped modules; keep what is there.
+	for i := range m.samples {
+		s := &m.samples[i]
+		raw := make([]byte, len(s.data))
+		n, _ := io.ReadFull(r, raw)
+		s.data = s.data[:n]
+		for j := range s.data {
+			s.data[j] = int8(raw[j])
+		}
+		if s.loopLen > 2 {
+			s.loopStart = min(s.loopStart, len(s.data))
+			s.loopLen = min(s.loopLen, len(s.data)-s.loopStart)
+		} else {
+			s.loopLen = 0
+		}
+	}
+	return m, nil
+}
+
+type modChannel struct {
+	sample     *modSample
+	pos        float64
+	period     int
+	outPeriod  int
+	target     int
+	finetune   int
+	volume     int
+	portaSpeed int
+	vibPos     int
+	vibSpeed   int
+	vibDepth   int
+	note       modNote
+}
+
+type modPlayer struct {
+	m          *module
+	speed, bpm int
+	order, row int
+	tick       int
+	ch         []modChannel
+
+	jump      bool
+	jumpOrder int
+	jumpRow   int
+}
+
+// finetunePeriod applies a sample's finetune, in eighths of a semitone.
+func finetunePeriod(period, finetune int) int {
+	return int(math.Round(float64(period) * math.Pow(2, -float64(finetune)/96)))
+}
+
+func (p *modPlayer) startRow() {
+	pattern := p.m.patterns[p.m.orders[p.order]]
+	for c := range p.ch {
+		ch := &p.ch[c]
+		n := pattern[p.row*p.m.channels+c]
+		ch.note = n
+
+		if n.sample > 0 && n.sample <= len(p.m.samples) {
+			ch.sample = &p.m.samples[n.sample-1]
+			ch.volume = ch.sample.volume
+			ch.finetune = ch.sample.finetune
+		}
+		if n.period > 0 {
+			period := finetunePeriod(n.period, ch.finetune)
+			if n.effect == 0x3 || n.effect == 0x5 {
+				ch.target = period
+			} else {
+				ch.period = period
+				ch.pos = 0
+				ch.vibPos = 0
+				if n.effect == 0x9 {
+					ch.pos = float64(n.param * 256)
+				}
+			}
+		}
+
+		hi, lo := n.param>>4, n.param&0x0f
+		switch n.effect {
+		case 0x3:
+			if n.param > 0 {
+				ch.portaSpeed = n.param
+			}
+		case 0x4:
+			if hi > 0 {
+				ch.vibSpeed = hi
+			}
+			if lo > 0 {
+				ch.vibDepth = lo
+			}
+		case 0xb:
+			p.jump, p.jumpOrder, p.jumpRow = true, n.param, 0
+		case 0xc:
+			ch.volume = min(n.param, 64)
+		case 0xd:
+			if !p.jump {
+				p.jump, p.jumpOrder = true, p.order+1
+			}
+			p.jumpRow = hi*10 + lo
+		case 0xe:
+			switch hi {
+			case 0x1:
+				ch.period = max(ch.period-lo, 113)
+			case 0x2:
+				ch.period = min(ch.period+lo, 856)
+			case 0xa:
+				ch.volume = min(ch.volume+lo, 64)
+			case 0xb:
+				ch.volume = max(ch.volume-lo, 0)
+			}
+		case 0xf:
+			if n.param > 0 && n.param < 32 {
+				p.speed = n.param
+			} else if n.param >= 32 {
+				p.bpm = n.param
+			}
+		}
+		ch.outPeriod = ch.period
+	}
+}
+
+// updateEffects runs the effects that change a note between rows.
+func (p *modPlayer) updateEffects() {
+	for c := range p.ch {
+		ch := &p.ch[c]
+		ch.outPeriod = ch.period
+		n := ch.note
+		hi, lo := n.param>>4, n.param&0x0f
+
+		switch n.effect {
+		case 0x0:
+			if n.param != 0 {
+				semitones := [3]int{0, hi, lo}[p.tick%3]
+				ch.outPeriod = int(float64(ch.period) * math.Pow(2, -float64(semitones)/12))
+			}
+		case 0x1:
+			ch.period = max(ch.period-n.param, 113)
+			ch.outPeriod = ch.period
+		case 0x2:
+			ch.period = min(ch.period+n.param, 856)
+			ch.outPeriod = ch.period
+		case 0x3:
+			ch.tonePorta()
+		case 0x4:
+			ch.vibrato()
+		case 0x5:
+			ch.tonePorta()
+			ch.volumeSlide(hi, lo)
+		case 0x6:
+			ch.vibrato()
+			ch.volumeSlide(hi, lo)
+		case 0xa:
+			ch.volumeSlide(hi, lo)
+		case 0xe:
+			if hi == 0xc && p.tick == lo {
+				ch.volume = 0
+			}
+		}
+	}
+}
+
+func (ch *modChannel) tonePorta() {
+	if ch.target == 0 {
+		return
+	}
+	if ch.period < ch.target {
+		ch.period = min(ch.period+ch.portaSpeed, ch.target)
+	} else {
+		ch.period = max(ch.period-ch.portaSpeed, ch.target)
+	}
+	ch.outPeriod = ch.period
+}
+
+func (ch *modChannel) vibrato() {
+	ch.vibPos = (ch.vibPos + ch.vibSpeed) % 64
+	delta := math.Sin(2*math.Pi*float64(ch.vibPos)/64) * 255 * float64(ch.vibDepth) / 128
+	ch.outPeriod = ch.period + int(delta)
+}
+
+func (ch *modChannel) volumeSlide(up, down int) {
+	if up > 0 {
+		ch.volume = min(ch.volume+up, 64)
+	} else {
+		ch.volume = max(ch.volume-down, 0)
+	}
+}
+
+// mix appends one tick of audio to pcm. Channels are panned hard left and
+// right in the Amiga's LRRL order, softened by mixing a quarter of each side
+// into the other.
+func (p *modPlayer) mix(pcm []byte) []byte {
+	frames := sampleRate * 5 / (p.bpm * 2)
+	for range frames {
+		var left, right float64
+		for c := range p.ch {
+			ch := &p.ch[c]
+			s := ch.sample
+			if s == nil || ch.outPeriod <= 0 || ch.volume == 0 {
+				continue
+			}
+			if s.loopLen > 0 && ch.pos >= float64(s.loopStart+s.loopLen) {
+				ch.pos -= float64(s.loopLen)
+			}
+			i := int(ch.pos)
+			if i >= len(s.data) {
+				continue
+			}
+
+			v := float64(s.data[i]) / 128 * float64(ch.volume) / 64
+			if c%4 == 0 || c%4 == 3 {
+				left += v
+			} else {
+				right += v
+			}
+			ch.pos += paulaClock / float64(ch.outPeriod) / sampleRate
+		}
+
+		gain := 1.6 / float64(p.m.channels)
+		l := min(max((left+right*0.25)*gain, -1), 1)
+		r := min(max((right+left*0.25)*gain, -1), 1)
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(l*math.MaxInt16)))
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(r*math.MaxInt16)))
+	}
+	return pcm
+}
+
+// nextRow moves to the next row, following pattern breaks and position
+// jumps. Past the last order the song returns to its restart position.
+func (p *modPlayer) nextRow() {
+	if p.jump {
+		p.order, p.row = p.jumpOrder, p.jumpRow
+		p.jump = false
+	} else if p.row++; p.row >= 64 {
+		p.order, p.row = p.order+1, 0
+	}
+	if p.order >= len(p.m.orders) {
+		p.order = p.m.restart
+	}
+	if p.row >= 64 {
+		p.row = 0
+	}
+}
+
+// moduleStream is a rendered module with the point its loop starts at.
+type moduleStream struct {
+	pcmStream
+	loopStart int64
+}
+
+func (s moduleStream) Loop() (int64, int64) {
+	return s.loopStart, s.Length() - s.loopStart
+}
+
+func decodeModule(r io.Reader) (audioStream, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	m, err := parseModule(data)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &modPlayer{m: m, speed: 6, bpm: 125, ch: make([]modChannel, m.channels)}
+	var pcm []byte
+	played := make(map[[2]int]int64)
+	for {
+		if p.tick == 0 {
+			pos := [2]int{p.order, p.row}
+			if start, ok := played[pos]; ok {
+				return moduleStream{pcmStream{bytes.NewReader(pcm)}, start}, nil
+			}
+			played[pos] = int64(len(pcm))
+			p.startRow()
+		} else {
+			p.updateEffects()
+		}
+
+		pcm = p.mix(pcm)
+		if len(pcm) >= moduleMaxLength {
+			return moduleStream{pcmStream{bytes.NewReader(pcm)}, 0}, nil
+		}
+
+		if p.tick++; p.tick >= p.speed {
+			p.tick = 0
+			p.nextRow()
+		}
+	}
+}
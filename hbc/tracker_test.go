@@ -0,0 +1,86 @@
+package hbc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// testModule builds a four channel module with one pattern and one sample,
+// playing a note with effect and param on its first row.
+func testModule(effect, param byte) []byte {
+	b := make([]byte, 1084+64*4*4)
+	copy(b, "test")
+	sample := b[20:50]
+	binary.BigEndian.PutUint16(sample[22:], 16) // length in words
+	sample[25] = 64
+	binary.BigEndian.PutUint16(sample[26:], 4)
+	binary.BigEndian.PutUint16(sample[28:], 8)
+	b[950] = 1
+	copy(b[1080:], "M.K.")
+	note := b[1084:]
+	note[0], note[1] = 0x11, 0xac // sample 1, period 428
+	note[2], note[3] = 0x00|effect, param
+	for i := range 32 {
+		b = append(b, byte(i*8))
+	}
+	return b
+}
+
+// fuzzModuleTicks is how much of a module FuzzMOD plays, enough for every
+// row of a pattern at the default speed.
+const fuzzModuleTicks = 64 * 6
+
+func FuzzMOD(f *testing.F) {
+	f.Add(testModule(0, 0))
+	f.Add(testModule(0x9, 0xff))
+	f.Add(testModule(0xb, 0x7f))
+	f.Add(testModule(0xd, 0x99))
+	f.Add(testModule(0xf, 0x01))
+	f.Add(testModule(0x3, 0x10))
+	eight := testModule(0x4, 0x44)
+	copy(eight[1080:], "8CHN")
+	f.Add(eight)
+	f.Add(make([]byte, 1084))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m, err := parseModule(data)
+		if err != nil {
+			return
+		}
+		for _, o := range m.orders {
+			if o >= len(m.patterns) {
+				t.Fatalf("order %d names pattern %d of %d", o, o, len(m.patterns))
+			}
+		}
+		// Playing it follows the same steps as decodeModule, without
+		// rendering the whole song. Only the first tick of each row is
+		// mixed, which keeps a slow song from taking seconds per input.
+		p := &modPlayer{m: m, speed: 6, bpm: 125, ch: make([]modChannel, m.channels)}
+		var pcm []byte
+		for range fuzzModuleTicks {
+			if p.tick == 0 {
+				p.startRow()
+				pcm = p.mix(pcm[:0])
+			} else {
+				p.updateEffects()
+			}
+			if p.tick++; p.tick >= p.speed {
+				p.tick = 0
+				p.nextRow()
+			}
+		}
+	})
+}
+
+// TestXMIsRejected checks that an XM module is refused by name, whether it
+// has its own extension or was renamed to .mod.
+func TestXMIsRejected(t *testing.T) {
+	xm := append([]byte("Extended Module: test"), make([]byte, 1084)...)
+	for _, p := range []string{"audio/loop.xm", "audio/loop.mod"} {
+		if _, err := decodeAudioFile(bytes.NewReader(xm), p); !errors.Is(err, errXM) {
+			t.Errorf("decoding %s: got %v, want %v", p, err, errXM)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package hbc
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const twitchAddr = "irc.chat.twitch.tv:6667"
+
+type TwitchConfig struct {
+	Enabled bool   `json:"enabled"`
+	Channel string `json:"channel"`
+	// RewardID limits bubbles to redemptions of one channel point reward.
+	// The reward has to ask the viewer for text, otherwise Twitch does not
+	// post it to chat. Empty means every chat message.
+	RewardID string `json:"reward_id,omitempty"`
+	// Rate is the number of bubbles per second, with short bursts of up to
+	// Burst bubbles. Messages beyond that are dropped.
+	Rate  float64 `json:"rate,omitempty"`
+	Burst int     `json:"burst,omitempty"`
+	// Bubble is the texture used for chat bubbles; empty picks random ones.
+	Bubble     string `json:"bubble,omitempty"`
+	LabelColor string `json:"label_color,omitempty"`
+}
+
+// ircMessage is a parsed IRC line with IRCv3 tags.
+type ircMessage struct {
+	tags    map[string]string
+	prefix  string
+	command string
+	params  []string
+}
+
+func parseIRC(line string) ircMessage {
+	var m ircMessage
+	line = strings.TrimRight(line, "\r\n")
+
+	if strings.HasPrefix(line, "@") {
+		var tags string
+		tags, line, _ = strings.Cut(line[1:], " ")
+		m.tags = make(map[string]string)
+		for _, tag := range strings.Split(tags, ";") {
+			k, v, _ := strings.Cut(tag, "=")
+			m.tags[k] = v
+		}
+	}
+	if strings.HasPrefix(line, ":") {
+		m.prefix, line, _ = strings.Cut(line[1:], " ")
+	}
+
+	line, trailing, hasTrailing := strings.Cut(line, " :")
+	fields := strings.Fields(line)
+	if len(fields) > 0 {
+		m.command, m.params = fields[0], fields[1:]
+	}
+	if hasTrailing {
+		m.params = append(m.params, trailing)
+	}
+	return m
+}
+
+// sender returns the display name of a PRIVMSG's author.
+func (m ircMessage) sender() string {
+	if name := m.tags["display-name"]; name != "" {
+		return name
+	}
+	nick, _, _ := strings.Cut(m.prefix, "!")
+	return nick
+}
+
+// runTwitchChat joins the channel anonymously and sends the author of every
+// matching message to names, reconnecting with a backoff. The backoff starts
+// over once a connection gets through the login, so that an outage long ago
+// does not slow down every later reconnect.
+func runTwitchChat(cfg TwitchConfig, names chan<- string) {
+	channel := "#" + strings.ToLower(strings.TrimPrefix(cfg.Channel, "#"))
+	const minBackoff = time.Second
+	backoff := minBackoff
+
+	for {
+		err := readTwitchChat(channel, cfg.RewardID, names, func() { backoff = minBackoff })
+		log.Printf("Warning: Twitch chat disconnected: %v\n", err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, time.Minute)
+	}
+}
+
+// readTwitchChat reads one connection's messages until it drops. It calls
+// loggedIn when the server welcomes the login.
+func readTwitchChat(channel, rewardID string, names chan<- string, loggedIn func()) error {
+	conn, err := net.DialTimeout("tcp", twitchAddr, 10*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// justinfan accounts are Twitch's read-only anonymous logins.
+	fmt.Fprintf(conn, "CAP REQ :twitch.tv/tags\r\nNICK justinfan%d\r\nJOIN %s\r\n", time.Now().UnixNano()%100000, channel)
+	log.Printf("Joined Twitch chat %s\n", channel)
+
+	r := bufio.NewReader(conn)
+	for {
+		conn.SetReadDeadline(time.Now().Add(10 * time.Minute))
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		m := parseIRC(line)
+		switch m.command {
+		case "001":
+			loggedIn()
+		case "PING":
+			fmt.Fprintf(conn, "PONG :%s\r\n", strings.Join(m.params, " "))
+		case "RECONNECT":
+			return fmt.Errorf("server requested a reconnect")
+		case "PRIVMSG":
+			if rewardID != "" && m.tags["custom-reward-id"] != rewardID {
+				continue
+			}
+			select {
+			case names <- m.sender():
+			default:
+			}
+		}
+	}
+}
+
+func (g *Intro) startTwitchChat(cfg TwitchConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	if cfg.Channel == "" {
+		log.Printf("Warning: Twitch chat is enabled but no channel is set\n")
+		return
+	}
+	if cfg.Rate <= 0 {
+		cfg.Rate = 2
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = 5
+	}
+
+	g.chat = make(chan string, 64)
+	g.chatCfg = cfg
+	g.chatTokens = float64(cfg.Burst)
+	g.chatBubbleType = -1
+	for i, bt := range g.bubbleTypes {
+		if bt.name == cfg.Bubble {
+			g.chatBubbleType = i
+		}
+	}
+	g.chatLabelColor = color.RGBA{40, 90, 140, 255}
+	if cfg.LabelColor != "" {
+		if c, err := parseHexColor(cfg.LabelColor); err == nil {
+			g.chatLabelColor = c
+		} else {
+			log.Printf("Warning: Invalid Twitch label color %q: %v\n", cfg.LabelColor, err)
+		}
+	}
+
+	go runTwitchChat(cfg, g.chat)
+}
+
+// updateChat turns queued chat messages into bubbles, limited by a token
+// bucket refilled at the configured rate.
+func (g *Intro) updateChat() {
+	if g.chat == nil {
+		return
+	}
+	g.chatTokens = min(g.chatTokens+g.chatCfg.Rate/60, float64(g.chatCfg.Burst))
+
+	for {
+		select {
+		case name := <-g.chat:
+			if g.chatTokens < 1 {
+				continue
+			}
+			g.chatTokens--
+			g.spawnBubble(g.chatBubbleType, name)
+		default:
+			return
+		}
+	}
+}
+
+func (g *Intro) drawBubbleLabel(screen *ebiten.Image, b Bubble, x, y, alpha float64) {
+	face := uiFace(12)
+	w, _ := text.Measure(b.label, face, 0)
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x-w/2, y)
+	op.GeoM.Concat(g.view)
+	op.ColorScale.ScaleWithColor(g.chatLabelColor)
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	text.Draw(screen, b.label, face, op)
+}
+
+func parseHexColor(s string) (color.RGBA, error) {
+	var c color.RGBA
+	c.A = 255
+	s = strings.TrimPrefix(s, "#")
+	var err error
+	switch len(s) {
+	case 6:
+		_, err = fmt.Sscanf(s, "%02x%02x%02x", &c.R, &c.G, &c.B)
+	case 8:
+		_, err = fmt.Sscanf(s, "%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A)
+	default:
+		err = fmt.Errorf("want #rrggbb or #rrggbbaa")
+	}
+	return c, err
+}
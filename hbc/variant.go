@@ -0,0 +1,33 @@
+package hbc
+
+import "log"
+
+// variants are the intros the program can play. Each adds its own
+// animation layers; the timeline, music, overlays, scenes and export
+// commands are shared.
+var variants = map[string]func(g *Intro){
+	"hbc":     (*Intro).setupHBCLayers,
+	"bootmii": (*Intro).setupBootMiiLayers,
+}
+
+func (g *Intro) setupVariant(name string) {
+	if name == "" {
+		name = "hbc"
+	}
+	setup, ok := variants[name]
+	if !ok {
+		log.Printf("Warning: Unknown variant %q, using hbc\n", name)
+		setup = variants["hbc"]
+	}
+	setup(g)
+}
+
+func (g *Intro) setupHBCLayers() {
+	g.addLayer(LayerBackground, g.drawBackground)
+	g.addLayer(LayerBackground, g.drawFade)
+	g.addLayer(LayerWaves, g.drawWaves)
+	g.addLayer(LayerBubbles, g.drawBubbles)
+	g.addLayer(LayerTitle, g.drawTitle)
+	g.addLayer(LayerFlash, g.drawBoom)
+	g.addLayer(LayerFlash, g.drawParty)
+}
@@ -0,0 +1,163 @@
+package hbc
+
+import (
+	"image"
+	"image/color"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// The vector renderer draws the waves, the fade and the bubbles as paths
+// instead of textures. Paths are filled at the resolution of the target, so
+// they stay sharp at any render scale or window size. The title is art and
+// always comes from its texture.
+
+func (g *Intro) setRenderer(name string) {
+	switch name {
+	case "", "texture":
+		g.vectorRenderer = false
+	case "vector":
+		g.vectorRenderer = true
+	default:
+		log.Printf("Warning: Unknown renderer %q, using texture\n", name)
+		g.vectorRenderer = false
+	}
+}
+
+var vectorSource *ebiten.Image
+
+// solidSource is a white pixel to fill triangles from. It is cut from the
+// middle of a larger image so that linear filtering cannot sample its edge.
+func solidSource() *ebiten.Image {
+	if vectorSource == nil {
+		img := ebiten.NewImage(3, 3)
+		img.Fill(color.White)
+		vectorSource = img.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+	}
+	return vectorSource
+}
+
+// fillPath fills p, given in the local coordinates op.GeoM places, with c
+// scaled by op.ColorScale.
+func (g *Intro) fillPath(dst *ebiten.Image, p *vector.Path, c color.Color, op *ebiten.DrawImageOptions) {
+	vs, is := p.AppendVerticesAndIndicesForFilling(nil, nil)
+	g.drawVertices(dst, vs, is, func(int) color.Color { return c }, op)
+}
+
+// drawVertices transforms triangles like drawImage does a texture and colors
+// each vertex with vertexColor.
+func (g *Intro) drawVertices(dst *ebiten.Image, vs []ebiten.Vertex, is []uint16, vertexColor func(i int) color.Color, op *ebiten.DrawImageOptions) {
+	geoM := op.GeoM
+	geoM.Concat(g.view)
+	s := op.ColorScale
+	for i := range vs {
+		x, y := geoM.Apply(float64(vs[i].DstX), float64(vs[i].DstY))
+		vs[i].DstX, vs[i].DstY = float32(x), float32(y)
+		vs[i].SrcX, vs[i].SrcY = 1, 1
+		r, gr, b, a := vertexColor(i).RGBA()
+		vs[i].ColorR = float32(r) / 0xffff * s.R()
+		vs[i].ColorG = float32(gr) / 0xffff * s.G()
+		vs[i].ColorB = float32(b) / 0xffff * s.B()
+		vs[i].ColorA = float32(a) / 0xffff * s.A()
+	}
+
+	top := &ebiten.DrawTrianglesOptions{
+		ColorScaleMode: ebiten.ColorScaleModePremultipliedAlpha,
+		Blend:          op.Blend,
+		FillRule:       ebiten.FillRuleEvenOdd,
+		AntiAlias:      true,
+	}
+	if top.Blend == (ebiten.Blend{}) {
+		top.Blend = g.blend
+	}
+	dst.DrawTriangles(vs, is, solidSource(), top)
+}
+
+// crestSegment is how far apart the points along a wave crest lie, in
+// texture pixels. The sine is gentle enough that this looks smooth.
+const crestSegment = 8
+
+// drawWave draws the wave texture name with op, as a path when the vector
+// renderer has a shape for it.
+func (g *Intro) drawWave(dst *ebiten.Image, name string, op *ebiten.DrawImageOptions) {
+	if !g.vectorRenderer {
+		g.drawImage(dst, g.texture(name), op)
+		return
+	}
+
+	var p vector.Path
+	var c color.Color = color.White
+	if s, ok := waveShapes[name]; ok {
+		w := float64(s.width)
+		p.MoveTo(0, float32(s.edge(0)))
+		for x := crestSegment; x <= s.width; x += crestSegment {
+			p.LineTo(float32(x), float32(s.edge(float64(x))))
+		}
+		p.LineTo(float32(w), float32(s.height))
+		p.LineTo(0, float32(s.height))
+		p.Close()
+		c = s.color
+	} else if s, ok := foamShapes[name]; ok {
+		var lower []float32
+		for x := 0; x <= s.width; x += crestSegment {
+			mid, half := s.band(float64(x))
+			if x == 0 {
+				p.MoveTo(0, float32(mid-half))
+			} else {
+				p.LineTo(float32(x), float32(mid-half))
+			}
+			lower = append(lower, float32(x), float32(mid+half))
+		}
+		for i := len(lower) - 2; i >= 0; i -= 2 {
+			p.LineTo(lower[i], lower[i+1])
+		}
+		p.Close()
+		c = premultiply(color.RGBA{255, 255, 255, 255}, s.alpha)
+	} else {
+		g.drawImage(dst, g.texture(name), op)
+		return
+	}
+	g.fillPath(dst, &p, c, op)
+}
+
+// drawVectorFade draws the water column as a quad from (0, 0) to (1, 1)
+// placed by op, blending from the top color to the bottom one.
+func (g *Intro) drawVectorFade(dst *ebiten.Image, op *ebiten.DrawImageOptions) {
+	vs := []ebiten.Vertex{{DstX: 0, DstY: 0}, {DstX: 1, DstY: 0}, {DstX: 0, DstY: 1}, {DstX: 1, DstY: 1}}
+	is := []uint16{0, 1, 2, 1, 3, 2}
+	g.drawVertices(dst, vs, is, func(i int) color.Color {
+		if i < 2 {
+			return waterBack
+		}
+		return waterBottom
+	}, op)
+}
+
+// drawVectorBubble draws a bubble of the given diameter, as the clear
+// bubble style looks: a faint film, a bright rim and a highlight to the
+// upper left.
+func (g *Intro) drawVectorBubble(dst *ebiten.Image, size float64, op *ebiten.DrawImageOptions) {
+	r := float32(size / 2)
+	white := func(a float64) color.Color { return premultiply(color.RGBA{255, 255, 255, 255}, a) }
+
+	var film vector.Path
+	film.Arc(r, r, r, 0, 2*math.Pi, vector.Clockwise)
+	film.Close()
+	g.fillPath(dst, &film, white(0.06), op)
+
+	// The rim ring is the space between two circles.
+	var rim vector.Path
+	rim.Arc(r, r, r, 0, 2*math.Pi, vector.Clockwise)
+	rim.Close()
+	rim.Arc(r, r, r*0.85, 0, 2*math.Pi, vector.Clockwise)
+	rim.Close()
+	g.fillPath(dst, &rim, white(0.45), op)
+
+	var highlight vector.Path
+	highlight.Arc(r*0.6, r*0.6, r*0.18, 0, 2*math.Pi, vector.Clockwise)
+	highlight.Close()
+	g.fillPath(dst, &highlight, white(0.7), op)
+}
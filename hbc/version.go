@@ -0,0 +1,77 @@
+package hbc
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X golm/hbc.version=1.2.0 -X golm/hbc.commit=$(git rev-parse --short HEAD) -X golm/hbc.date=$(date -u +%Y-%m-%d)"
+//
+// Anything left empty is filled in from the build info the Go toolchain
+// embeds.
+var (
+	version string
+	commit  string
+	date    string
+)
+
+var buildVersion, buildCommit, buildDate = resolveBuildInfo()
+
+func resolveBuildInfo() (v, c, d string) {
+	v, c, d = version, commit, date
+	modified := false
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			v = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if c == "" {
+					c = s.Value
+				}
+			case "vcs.time":
+				if d == "" {
+					d = s.Value
+				}
+			case "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+	}
+
+	if v == "" {
+		v = "dev"
+	}
+	if len(c) > 12 {
+		c = c[:12]
+	}
+	if c != "" && modified && commit == "" {
+		c += "-dirty"
+	}
+	return v, c, d
+}
+
+func versionString() string {
+	s := "go-hbc-intro " + buildVersion
+	if buildCommit != "" {
+		s += " (" + buildCommit
+		if buildDate != "" {
+			s += ", " + buildDate
+		}
+		s += ")"
+	} else if buildDate != "" {
+		s += " (" + buildDate + ")"
+	}
+	return s
+}
+
+func runVersion(args []string) error {
+	fmt.Println(versionString())
+	fmt.Printf("%s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return nil
+}
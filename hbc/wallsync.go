@@ -0,0 +1,225 @@
+package hbc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// A video wall is several machines, each driving one screen, that together
+// show the intro on one large canvas. One of them leads: every tick it
+// broadcasts its animation clock over UDP, and the followers play the frame
+// it is on. Each machine draws only its own viewport of the canvas.
+//
+// A clock packet is "HBCW", then the seed as an int64, the loop and the
+// frame as uint32s, the ticks as a uint64, the theme index and 1 when
+// paused, all big endian.
+
+// WallConfig makes this machine one screen of a video wall.
+type WallConfig struct {
+	// Role is "leader" for the machine whose clock the others follow, or
+	// "follower". Empty plays on its own.
+	Role string `json:"role,omitempty"`
+	// Addr is where the leader sends its clock and whose port the followers
+	// listen on. It defaults to broadcasting to the local network.
+	Addr string `json:"addr,omitempty"`
+	// Viewport is the part of the canvas this machine shows, as
+	// "x,y,width,height" in fractions of the canvas, such as "1/3,0,1/3,1"
+	// for the middle of three screens side by side. The canvas is laid out
+	// from Aspect.
+	Viewport string `json:"viewport,omitempty"`
+}
+
+const (
+	defaultWallAddr = "255.255.255.255:4300"
+	wallMagic       = "HBCW"
+	wallPacketSize  = 30
+	// wallTimeout is how long a follower keeps to a leader it no longer
+	// hears before playing on its own clock.
+	wallTimeout = time.Second
+	// wallSettleTicks is how many ticks in a row a follower has to be off
+	// before it jumps to the leader's frame. Packets arrive at the same rate
+	// as ticks but not in step with them, so a single tick's difference
+	// comes and goes on its own.
+	wallSettleTicks = 3
+	// wallSeekFrames is how far a follower may be off before the music is
+	// moved along with the picture.
+	wallSeekFrames = 6
+)
+
+// wallClock is the leader's position in the animation.
+type wallClock struct {
+	seed   int64
+	loop   int
+	frame  int
+	ticks  int
+	theme  int
+	paused bool
+	// received is when a follower got it.
+	received time.Time
+}
+
+type wallSync struct {
+	conn   net.PacketConn
+	leader bool
+	to     net.Addr
+	// failed keeps a leader that cannot send from logging every tick.
+	failed bool
+
+	latest atomic.Pointer[wallClock]
+	locked bool
+	off    int
+}
+
+func (c wallClock) encode() []byte {
+	b := make([]byte, 0, wallPacketSize)
+	b = append(b, wallMagic...)
+	b = binary.BigEndian.AppendUint64(b, uint64(c.seed))
+	b = binary.BigEndian.AppendUint32(b, uint32(c.loop))
+	b = binary.BigEndian.AppendUint32(b, uint32(c.frame))
+	b = binary.BigEndian.AppendUint64(b, uint64(c.ticks))
+	b = append(b, byte(c.theme))
+	if c.paused {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
+
+func decodeWallClock(b []byte) (wallClock, bool) {
+	if len(b) != wallPacketSize || string(b[:4]) != wallMagic {
+		return wallClock{}, false
+	}
+	return wallClock{
+		seed:   int64(binary.BigEndian.Uint64(b[4:])),
+		loop:   int(binary.BigEndian.Uint32(b[12:])),
+		frame:  int(binary.BigEndian.Uint32(b[16:])),
+		ticks:  int(binary.BigEndian.Uint64(b[20:])),
+		theme:  int(b[28]),
+		paused: b[29] == 1,
+	}, true
+}
+
+// startWall opens the socket for the configured role.
+func (g *Intro) startWall(c WallConfig) {
+	if c.Role == "" {
+		return
+	}
+	addr := c.Addr
+	if addr == "" {
+		addr = defaultWallAddr
+	}
+	w := &g.wall
+	var err error
+	switch c.Role {
+	case "leader":
+		w.leader = true
+		if w.to, err = net.ResolveUDPAddr("udp4", addr); err != nil {
+			break
+		}
+		w.conn, err = net.ListenPacket("udp4", ":0")
+	case "follower":
+		var port string
+		if _, port, err = net.SplitHostPort(addr); err != nil {
+			break
+		}
+		if w.conn, err = net.ListenPacket("udp4", ":"+port); err == nil {
+			go w.listen()
+		}
+	default:
+		err = fmt.Errorf("unknown role %q, want leader or follower", c.Role)
+	}
+	if err != nil {
+		log.Printf("Warning: Could not join the video wall on %s: %v\n", addr, err)
+		w.conn = nil
+	}
+}
+
+// listen keeps the newest clock the leader sent.
+func (w *wallSync) listen() {
+	buf := make([]byte, 64)
+	for {
+		n, _, err := w.conn.ReadFrom(buf)
+		if err != nil {
+			log.Printf("Warning: Video wall listener stopped: %v\n", err)
+			return
+		}
+		c, ok := decodeWallClock(buf[:n])
+		if !ok {
+			continue
+		}
+		c.received = time.Now()
+		w.latest.Store(&c)
+	}
+}
+
+// following reports whether a leader sets the clock, which then stands in
+// for the wall clock that catchUp measures against.
+func (w *wallSync) following() bool {
+	if w.conn == nil || w.leader {
+		return false
+	}
+	c := w.latest.Load()
+	return c != nil && time.Since(c.received) < wallTimeout
+}
+
+// updateWall runs before the tick advances, so that the leader sends the
+// frame both it and the followers are about to step from.
+func (g *Intro) updateWall() {
+	w := &g.wall
+	if w.conn == nil {
+		return
+	}
+	if w.leader {
+		c := wallClock{seed: g.seed, loop: g.loopCount, frame: g.count, ticks: g.ticks, theme: g.themeIndex, paused: g.paused}
+		if _, err := w.conn.WriteTo(c.encode(), w.to); err != nil && !w.failed {
+			log.Printf("Warning: Could not send the clock to the video wall: %v\n", err)
+			w.failed = true
+		}
+		return
+	}
+	if w.following() {
+		g.followWall(*w.latest.Load())
+	}
+}
+
+func (g *Intro) followWall(c wallClock) {
+	w := &g.wall
+	if c.seed != g.seed {
+		g.seed = c.seed
+		g.regenerateBubbles()
+	}
+	if c.theme != g.themeIndex && c.theme < len(themes) {
+		g.selectTheme(c.theme)
+	}
+	if c.paused != g.paused {
+		g.setPaused(c.paused)
+	}
+
+	// A packet read more than half a tick after it came in is closer to the
+	// leader's next frame than to the one it was sent on.
+	frame, ticks := c.frame, c.ticks
+	if !c.paused && time.Since(c.received) > time.Second/120 {
+		frame++
+		ticks++
+	}
+	loop := c.loop
+	if frame >= loopEnd {
+		frame, loop = loopStart, loop+1
+	}
+	off := (loop-g.loopCount)*(loopEnd-loopStart) + frame - g.count
+	if off == 0 {
+		w.off = 0
+		return
+	}
+	if w.off++; w.locked && w.off < wallSettleTicks && abs(off) <= wallSeekFrames {
+		return
+	}
+	g.count, g.loopCount, g.ticks = frame, loop, ticks
+	if !w.locked || abs(off) > wallSeekFrames {
+		g.seekAudio()
+	}
+	w.locked, w.off = true, 0
+}
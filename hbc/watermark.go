@@ -0,0 +1,125 @@
+package hbc
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/png"
+	"log"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const watermarkMargin = 12
+
+type WatermarkConfig struct {
+	Text string `json:"text,omitempty"`
+	// Image is a PNG file drawn instead of, or above, the text.
+	Image string `json:"image,omitempty"`
+	// Font is a TTF or OTF file for the text. Empty uses the UI font.
+	Font  string  `json:"font,omitempty"`
+	Size  float64 `json:"size,omitempty"`
+	Color string  `json:"color,omitempty"`
+	// Position is a corner name as for the clock, or "center". Defaults to
+	// "top-left".
+	Position string  `json:"position,omitempty"`
+	Opacity  float64 `json:"opacity,omitempty"`
+}
+
+type watermark struct {
+	cfg   WatermarkConfig
+	face  *text.GoTextFace
+	color color.RGBA
+	src   image.Image
+	img   *ebiten.Image
+}
+
+func (g *Intro) setupWatermark() {
+	cfg := g.cfg.Watermark
+	if cfg.Text == "" && cfg.Image == "" {
+		return
+	}
+	if cfg.Size <= 0 {
+		cfg.Size = 16
+	}
+	if cfg.Opacity <= 0 || cfg.Opacity > 1 {
+		cfg.Opacity = 1
+	}
+	if cfg.Position == "" {
+		cfg.Position = "top-left"
+	}
+
+	w := &watermark{cfg: cfg, face: uiFace(cfg.Size), color: color.RGBA{255, 255, 255, 255}}
+	if cfg.Color != "" {
+		c, err := parseHexColor(cfg.Color)
+		if err != nil {
+			log.Printf("Warning: Invalid watermark color %q: %v\n", cfg.Color, err)
+		} else {
+			w.color = c
+		}
+	}
+	if cfg.Font != "" {
+		data, err := os.ReadFile(cfg.Font)
+		if err == nil {
+			var src *text.GoTextFaceSource
+			src, err = text.NewGoTextFaceSource(bytes.NewReader(data))
+			if err == nil {
+				w.face = &text.GoTextFace{Source: src, Size: cfg.Size}
+			}
+		}
+		if err != nil {
+			log.Printf("Warning: Could not load watermark font: %v\n", err)
+		}
+	}
+	if cfg.Image != "" {
+		f, err := os.Open(cfg.Image)
+		if err == nil {
+			w.src, _, err = image.Decode(f)
+			f.Close()
+		}
+		if err != nil {
+			log.Printf("Warning: Could not load watermark image: %v\n", err)
+		}
+	}
+
+	g.watermark = w
+}
+
+// drawWatermark goes last so it sits above every other layer, including the
+// overlays.
+func (g *Intro) drawWatermark(ui *ebiten.Image) {
+	w := g.watermark
+	if w == nil {
+		return
+	}
+	if w.src != nil && w.img == nil {
+		w.img = ebiten.NewImageFromImage(w.src)
+	}
+
+	var iw, ih, tw, th float64
+	if w.img != nil {
+		iw, ih = float64(w.img.Bounds().Dx()), float64(w.img.Bounds().Dy())
+	}
+	if w.cfg.Text != "" {
+		tw, th = text.Measure(w.cfg.Text, w.face, 0)
+	}
+	bw := max(iw, tw)
+	x, y := g.cornerPosition(w.cfg.Position, bw, ih+th, watermarkMargin)
+
+	if w.img != nil {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(x+(bw-iw)/2, y)
+		op.ColorScale.ScaleAlpha(float32(w.cfg.Opacity))
+		op.Filter = ebiten.FilterLinear
+		ui.DrawImage(w.img, op)
+	}
+	if w.cfg.Text != "" {
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(x+(bw-tw)/2, y+ih)
+		op.ColorScale.ScaleWithColor(w.color)
+		op.ColorScale.ScaleAlpha(float32(w.cfg.Opacity))
+		text.Draw(ui, w.cfg.Text, w.face, op)
+	}
+}
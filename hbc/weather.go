@@ -0,0 +1,271 @@
+package hbc
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// The weather integration looks up the current conditions on Open-Meteo,
+// which needs no API key, and paints the intro to match: grey with rain
+// falling into the water when it rains, bright when it is clear. While the
+// weather cannot be fetched the intro goes back to its own theme.
+const (
+	weatherURL     = "https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&current=weather_code,is_day"
+	weatherTimeout = 10 * time.Second
+
+	rainDrops = 160
+	// rainFadeFrames is how long the rain takes to start or stop.
+	rainFadeFrames = 180
+	rainWind       = 1.5
+	rainLength     = 14
+	rainAlpha      = 0.45
+)
+
+var rainColor = color.RGBA{225, 232, 245, 255}
+
+type WeatherConfig struct {
+	Enabled bool `json:"enabled"`
+	// Latitude and Longitude are where to look up the weather, in degrees.
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// Refresh is how often to look again, in minutes. Defaults to 15.
+	Refresh int `json:"refresh_minutes,omitempty"`
+}
+
+type weatherKind int
+
+const (
+	// weatherUnknown is reported when the lookup fails.
+	weatherUnknown weatherKind = iota
+	weatherClear
+	weatherCloudy
+	weatherRain
+)
+
+type weatherReport struct {
+	kind  weatherKind
+	night bool
+}
+
+// weatherPalettes are the colors for each kind of weather, by day and by
+// night. Clear nights use the night theme.
+var weatherPalettes = map[weatherKind][2]Palette{
+	weatherClear: {
+		{Background: color.RGBA{255, 255, 255, 255}, Tint: color.RGBA{255, 251, 238, 255}},
+	},
+	weatherCloudy: {
+		{Background: color.RGBA{214, 220, 226, 255}, Tint: color.RGBA{212, 220, 230, 255}},
+		{Background: color.RGBA{26, 30, 40, 255}, Tint: color.RGBA{105, 115, 140, 255}},
+	},
+	weatherRain: {
+		{Background: color.RGBA{150, 158, 168, 255}, Tint: color.RGBA{165, 175, 188, 255}},
+		{Background: color.RGBA{22, 24, 30, 255}, Tint: color.RGBA{85, 92, 108, 255}},
+	},
+}
+
+type rainDrop struct {
+	x, y, speed float64
+}
+
+type weatherState struct {
+	reports chan weatherReport
+	shown   weatherReport
+	// rain goes from 0 to 1 as the rain starts.
+	rain  float64
+	drops []rainDrop
+	drop  *ebiten.Image
+}
+
+// weatherKindOf sorts a WMO weather code, as used by Open-Meteo.
+func weatherKindOf(code int) weatherKind {
+	switch {
+	case code <= 1:
+		return weatherClear
+	case code >= 51 && code <= 67, code >= 80 && code <= 82, code >= 95:
+		return weatherRain
+	default:
+		// Clouds, fog and snow.
+		return weatherCloudy
+	}
+}
+
+func fetchWeather(cfg WeatherConfig) (weatherReport, error) {
+	client := &http.Client{Timeout: weatherTimeout}
+	resp, err := client.Get(fmt.Sprintf(weatherURL, cfg.Latitude, cfg.Longitude))
+	if err != nil {
+		return weatherReport{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return weatherReport{}, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		Current struct {
+			WeatherCode *int `json:"weather_code"`
+			IsDay       int  `json:"is_day"`
+		} `json:"current"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return weatherReport{}, err
+	}
+	if body.Current.WeatherCode == nil {
+		return weatherReport{}, fmt.Errorf("no current weather in the response")
+	}
+	return weatherReport{
+		kind:  weatherKindOf(*body.Current.WeatherCode),
+		night: body.Current.IsDay == 0,
+	}, nil
+}
+
+// runWeather sends the weather to reports every refresh, or an unknown
+// report when it cannot be fetched.
+func runWeather(cfg WeatherConfig, reports chan<- weatherReport) {
+	refresh := time.Duration(cfg.Refresh) * time.Minute
+	for {
+		report, err := fetchWeather(cfg)
+		if err != nil {
+			log.Printf("Warning: Could not fetch the weather: %v\n", err)
+		}
+		select {
+		case reports <- report:
+		default:
+		}
+		time.Sleep(refresh)
+	}
+}
+
+func (g *Intro) startWeather(cfg WeatherConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	if cfg.Latitude == 0 && cfg.Longitude == 0 {
+		log.Printf("Warning: The weather is enabled but no latitude and longitude are set\n")
+		return
+	}
+	if cfg.Refresh <= 0 {
+		cfg.Refresh = 15
+	}
+
+	drop := ebiten.NewImage(1, rainLength)
+	drop.Fill(color.White)
+	g.weather = &weatherState{
+		reports: make(chan weatherReport, 4),
+		drop:    drop,
+	}
+	go runWeather(cfg, g.weather.reports)
+}
+
+// updateWeather fades to the palette for the latest report.
+func (g *Intro) updateWeather() {
+	w := g.weather
+	if w == nil {
+		return
+	}
+	for {
+		select {
+		case report := <-w.reports:
+			if report != w.shown {
+				w.shown = report
+				g.showWeather(report)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (g *Intro) showWeather(report weatherReport) {
+	if report.kind == weatherUnknown {
+		// Back to the theme, unless the theme key already did that.
+		if g.palette.custom {
+			g.selectTheme(g.themeIndex)
+		}
+		return
+	}
+	if report.kind == weatherClear && report.night {
+		if i, ok := themeIndex("night"); ok {
+			g.selectTheme(i)
+		}
+		return
+	}
+	p := weatherPalettes[report.kind][0]
+	if report.night {
+		p = weatherPalettes[report.kind][1]
+	}
+	g.fadeTo(p)
+	g.palette.custom = true
+}
+
+// updateRain moves the drops, starting and stopping them gradually as the
+// weather changes.
+func (g *Intro) updateRain() {
+	w := g.weather
+	if w == nil {
+		return
+	}
+	target := 0.0
+	if w.shown.kind == weatherRain && !g.reducedMotion {
+		target = 1
+	}
+	if w.rain < target {
+		w.rain = min(w.rain+1.0/rainFadeFrames, target)
+	} else {
+		w.rain = max(w.rain-1.0/rainFadeFrames, target)
+	}
+
+	want := int(w.rain * rainDrops)
+	water := g.waterLevel()
+	live := w.drops[:0]
+	for _, d := range w.drops {
+		d.x += rainWind
+		d.y += d.speed
+		if d.y < water {
+			live = append(live, d)
+		} else if len(live) < want {
+			live = append(live, g.newRainDrop(water))
+		}
+	}
+	w.drops = live
+	// New drops start anywhere in the sky so the rain does not arrive as
+	// one sheet.
+	for len(w.drops) < want {
+		d := g.newRainDrop(water)
+		d.y = rand.Float64() * water
+		w.drops = append(w.drops, d)
+	}
+}
+
+func (g *Intro) newRainDrop(water float64) rainDrop {
+	// Drops lean with the wind, so they start further left to cover the
+	// whole width by the time they reach the water.
+	lead := water / 7 * rainWind
+	return rainDrop{
+		x:     rand.Float64()*(g.width+lead) - lead,
+		y:     -rainLength,
+		speed: 6 + rand.Float64()*3,
+	}
+}
+
+func (g *Intro) drawRain(screen *ebiten.Image) {
+	w := g.weather
+	if w == nil || len(w.drops) == 0 {
+		return
+	}
+	for _, d := range w.drops {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Skew(math.Atan2(rainWind, d.speed), 0)
+		op.GeoM.Translate(d.x, d.y)
+		op.ColorScale.ScaleWithColor(rainColor)
+		op.ColorScale.ScaleAlpha(rainAlpha)
+		g.drawImage(screen, w.drop, op)
+	}
+}
@@ -0,0 +1,202 @@
+package hbc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// The Homebrew Channel accepts programs sent from a computer with wiiload
+// on TCP port 4299. The intro listens there too and shows the channel's
+// transfer dialog while something is sent. What is received is thrown away;
+// nothing is ever run.
+//
+// A transfer starts with "HAXX", the protocol version as two bytes and the
+// length of the arguments as a big endian uint16. Version 0.5 then sends the
+// compressed and uncompressed sizes as uint32s, older versions only the
+// size. The program and the arguments, NUL separated with the file name
+// first, follow.
+
+const (
+	wiiloadAddr = ":4299"
+	// wiiloadDoneFrames is how long the dialog stays after a transfer ends.
+	wiiloadDoneFrames = 3 * 60
+	wiiloadTimeout    = 30 * time.Second
+)
+
+type wiiloadTransfer struct {
+	mu       sync.Mutex
+	active   bool
+	received int64
+	total    int64
+	name     string
+	result   string
+	// shown counts down once the transfer is over.
+	shown int
+}
+
+func (t *wiiloadTransfer) update(f func(t *wiiloadTransfer)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	f(t)
+}
+
+func (g *Intro) startWiiload(enabled bool) {
+	if !enabled {
+		return
+	}
+	ln, err := net.Listen("tcp", wiiloadAddr)
+	if err != nil {
+		log.Printf("Warning: Could not listen for wiiload: %v\n", err)
+		return
+	}
+	g.wiiload = &wiiloadTransfer{}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				log.Printf("Warning: Wiiload listener stopped: %v\n", err)
+				return
+			}
+			// One transfer at a time, like the channel.
+			g.receiveWiiload(conn)
+		}
+	}()
+}
+
+func (g *Intro) receiveWiiload(conn net.Conn) {
+	defer conn.Close()
+	t := g.wiiload
+	t.update(func(t *wiiloadTransfer) {
+		t.active, t.received, t.total, t.name, t.result, t.shown = true, 0, 0, "", "", 0
+	})
+
+	err := readWiiload(conn, t)
+	t.update(func(t *wiiloadTransfer) {
+		t.active = false
+		t.shown = wiiloadDoneFrames
+		switch {
+		case err != nil:
+			t.result = "Transfer failed"
+			log.Printf("Warning: Wiiload transfer from %s failed: %v\n", conn.RemoteAddr(), err)
+		case t.name != "":
+			t.result = "Received " + t.name
+		default:
+			t.result = "Received"
+		}
+	})
+}
+
+func readWiiload(conn net.Conn, t *wiiloadTransfer) error {
+	conn.SetDeadline(time.Now().Add(wiiloadTimeout))
+	r := bufio.NewReader(conn)
+
+	var header struct {
+		Magic        [4]byte
+		Major, Minor uint8
+		ArgsLen      uint16
+		Size         uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return err
+	}
+	if string(header.Magic[:]) != "HAXX" {
+		return errors.New("not a wiiload transfer")
+	}
+	if header.Major != 0 || header.Minor > 5 {
+		return fmt.Errorf("unsupported wiiload version %d.%d", header.Major, header.Minor)
+	}
+	if header.Minor == 5 {
+		// The uncompressed size is only needed to unpack the program.
+		var uncompressed uint32
+		if err := binary.Read(r, binary.BigEndian, &uncompressed); err != nil {
+			return err
+		}
+	}
+	t.update(func(t *wiiloadTransfer) { t.total = int64(header.Size) })
+
+	buf := make([]byte, 32*1024)
+	for left := int64(header.Size); left > 0; {
+		conn.SetDeadline(time.Now().Add(wiiloadTimeout))
+		n, err := r.Read(buf[:min(left, int64(len(buf)))])
+		left -= int64(n)
+		t.update(func(t *wiiloadTransfer) { t.received += int64(n) })
+		if err != nil {
+			return err
+		}
+	}
+
+	args := make([]byte, header.ArgsLen)
+	if _, err := io.ReadFull(r, args); err != nil {
+		return err
+	}
+	name, _, _ := strings.Cut(string(args), "\x00")
+	t.update(func(t *wiiloadTransfer) { t.name = name })
+	return nil
+}
+
+func (g *Intro) updateWiiload() {
+	if g.wiiload == nil {
+		return
+	}
+	g.wiiload.update(func(t *wiiloadTransfer) {
+		if !t.active && t.shown > 0 {
+			t.shown--
+		}
+	})
+}
+
+func (g *Intro) drawWiiload(ui *ebiten.Image) {
+	t := g.wiiload
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	active, received, total, result, shown := t.active, t.received, t.total, t.result, t.shown
+	t.mu.Unlock()
+	if !active && shown == 0 {
+		return
+	}
+
+	const w, h = 360, 110
+	x, y := float32(g.width-w)/2, float32(g.height-h)/2
+	alpha := float32(1)
+	if !active {
+		alpha = min(float32(shown)/30, 1)
+	}
+	vector.DrawFilledRect(ui, x, y, w, h, scaleAlpha(color.RGBA{255, 255, 255, 235}, alpha), false)
+	vector.StrokeRect(ui, x, y, w, h, 2, scaleAlpha(appHoverBorder, alpha), false)
+
+	msg := "Receiving over TCP/IP..."
+	progress := float32(0)
+	if total > 0 {
+		progress = float32(received) / float32(total)
+		msg = fmt.Sprintf("%s %d / %d KiB", msg, received/1024, total/1024)
+	}
+	if !active {
+		msg, progress = result, 1
+	}
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(g.width/2, float64(y)+24)
+	op.PrimaryAlign = text.AlignCenter
+	op.ColorScale.ScaleWithColor(appTextColor)
+	op.ColorScale.ScaleAlpha(alpha)
+	text.Draw(ui, msg, uiFace(appFooterSize), op)
+
+	const barWidth, barHeight = 300, 10
+	bx, by := x+(w-barWidth)/2, y+h-36
+	vector.DrawFilledRect(ui, bx, by, barWidth, barHeight, scaleAlpha(color.RGBA{220, 220, 220, 255}, alpha), false)
+	vector.DrawFilledRect(ui, bx, by, barWidth*progress, barHeight, scaleAlpha(appHoverBorder, alpha), false)
+}
@@ -0,0 +1,73 @@
+package hbc
+
+import (
+	"bytes"
+	"image"
+	"log"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	defaultWindowTitle = "go-hbc-intro"
+	// defaultWindowIcon is the largest of the original bubbles.
+	defaultWindowIcon = "cbubble1.png"
+)
+
+// windowTitle is the title of the intro's window.
+func (g *Intro) windowTitle() string {
+	if g.cfg.WindowTitle != "" {
+		return g.cfg.WindowTitle
+	}
+	return defaultWindowTitle
+}
+
+// setWindowIcon sets the icon named by the config, from an image file or a
+// texture in the asset pack, or the default bubble. Platforms without window
+// icons ignore it.
+func (g *Intro) setWindowIcon() {
+	if g.windowIcon != nil {
+		ebiten.SetWindowIcon([]image.Image{g.windowIcon})
+		return
+	}
+	name := g.cfg.WindowIcon
+	if name == "" {
+		name = defaultWindowIcon
+	}
+	img, err := g.loadWindowIcon(name)
+	if err != nil {
+		// A pack without the default bubble keeps the system's icon.
+		if g.cfg.WindowIcon != "" {
+			log.Printf("Warning: Could not load window icon %s: %v\n", name, err)
+		}
+		return
+	}
+	ebiten.SetWindowIcon([]image.Image{img})
+}
+
+// SetWindowIcon makes img the icon of the intro's window, for programs that
+// embed an icon of their own rather than name one in the config. It can be
+// called before Run or while the intro runs.
+func (g *Intro) SetWindowIcon(img image.Image) {
+	g.windowIcon = img
+	ebiten.SetWindowIcon([]image.Image{img})
+}
+
+// loadWindowIcon loads name as a texture from the asset pack if it has one
+// by that name, and otherwise as an image file.
+func (g *Intro) loadWindowIcon(name string) (image.Image, error) {
+	if fileExists(g.assets, "img/"+name) || fileExists(g.assets, "img/"+svgPath(name)) {
+		img, _, err := decodeTexture(g.assets, name, 1)
+		if err != nil {
+			return nil, err
+		}
+		return img, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}